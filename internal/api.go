@@ -2,6 +2,7 @@ package internal
 
 import (
 	"job-scraper/internal/services/service_jobs"
+	"job-scraper/internal/services/service_schedule"
 	"job-scraper/internal/services/service_scraper"
 
 	"github.com/labstack/echo/v4"
@@ -13,16 +14,46 @@ func SubmitScrapeRequest(c echo.Context) error {
 	return service_scraper.StartJobScrapping(c)
 }
 
-func SubmitWorkdayCompanyToScrape(c echo.Context) error {
-	return service_scraper.AddWorkdayCompanyToScrapeList(c)
+// Scrapes a company from an add-company payload without saving anything, to check its config
+func PreviewCompany(c echo.Context) error {
+	return service_scraper.PreviewCompany(c)
 }
 
-func SubmitGreenhouseCompanyToScrape(c echo.Context) error {
-	return service_scraper.AddGreenhouseCompanyToScrapeList(c)
+// Scrapes a single company right away, whether or not it's enabled for scraping
+func ScrapeCompany(c echo.Context) error {
+	return service_scraper.ScrapeCompany(c)
 }
 
-func SubmitOracleCloudCompanyToScrape(c echo.Context) error {
-	return service_scraper.AddOracleCloudCompanyToScrapeList(c)
+// Adds a company for any registered provider, the provider is taken from the path
+func SubmitCompanyToScrape(c echo.Context) error {
+	return service_scraper.AddCompanyToScrapeList(c)
+}
+
+// Detects the ATS from a careers page URL and adds the company with the matching provider
+func SubmitAutoDetectedCompanyToScrape(c echo.Context) error {
+	return service_scraper.AddAutoDetectedCompanyToScrapeList(c)
+}
+
+// Scrape run endpoints
+func GetScrapeRuns(c echo.Context) error {
+	return service_scraper.GetScrapeRuns(c)
+}
+
+func GetScrapeRun(c echo.Context) error {
+	return service_scraper.GetScrapeRun(c)
+}
+
+func CancelScrapeRun(c echo.Context) error {
+	return service_scraper.CancelScrapeRun(c)
+}
+
+func StreamScrapeRunEvents(c echo.Context) error {
+	return service_scraper.StreamScrapeRunEvents(c)
+}
+
+// Lists the scheduled scrape and cleanup jobs with their next and last runs
+func GetSchedule(c echo.Context) error {
+	return service_schedule.GetSchedule(c)
 }
 
 // Job search endpoints
@@ -7,10 +7,17 @@ import "encoding/json"
 // 	PageSize int `query:"page_size" validate:"omitempty,min=1,max=100" default:"1"`
 // }
 
+// AddCompanyScrapeList holds the fields shared by every provider's add-company request
+type AddCompanyScrapeList struct {
+	Name string `json:"name"`
+}
+
 type AddWorkdayCompanyScrapeList struct {
 	Name       string          `json:"name"`
 	BaseUrl    string          `json:"base_url"`
 	ApiReqBody json.RawMessage `json:"req_body"`
+	// Optional careers browser URL, replaces base_url and, when req_body is empty, builds it from the URL filters
+	BrowserUrl string `json:"browser_url"`
 }
 
 type AddGreenhouseCompanyScrapeList struct {
@@ -23,6 +30,36 @@ type AddOracleCloudCompanyScrapeList struct {
 	BrowserUrl string `json:"browser_url"`
 }
 
+type AddLeverCompanyScrapeList struct {
+	Name string `json:"name"`
+	Site string `json:"site"`
+}
+
+type AddAshbyCompanyScrapeList struct {
+	Name  string `json:"name"`
+	Board string `json:"board"`
+}
+
+type AddSmartRecruitersCompanyScrapeList struct {
+	Name              string `json:"name"`
+	CompanyIdentifier string `json:"company_identifier"`
+}
+
+type AddJsonLDCompanyScrapeList struct {
+	Name       string `json:"name"`
+	CareersUrl string `json:"careers_url"`
+}
+
+type AddCustomCompanyScrapeList struct {
+	Name string          `json:"name"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+type AddAutoDetectedCompanyScrapeList struct {
+	Name       string `json:"name"`
+	CareersUrl string `json:"careers_url"`
+}
+
 type JobSearchRequest struct {
 	Company         string   `query:"company" json:"company"`
 	Title           string   `query:"title" json:"title"`
@@ -33,10 +70,36 @@ type JobSearchRequest struct {
 }
 
 type UpdateCompanyRequest struct {
-	Name                 string          `json:"name"`
-	BaseUrl              string          `json:"base_url"`
-	CareerSiteType       string          `json:"career_site_type"`
-	ApiRequestBody       json.RawMessage `json:"api_request_body"`
-	ApiRequestQueryParam string          `json:"api_request_query_param"`
-	ToScrape             *bool           `json:"to_scrape"`
+	Name                  string          `json:"name"`
+	BaseUrl               string          `json:"base_url"`
+	CareerSiteType        string          `json:"career_site_type"`
+	ApiRequestBody        json.RawMessage `json:"api_request_body"`
+	ApiRequestQueryParam  string          `json:"api_request_query_param"`
+	ToScrape              *bool           `json:"to_scrape"`
+	ScrapeIntervalMinutes *int            `json:"scrape_interval_minutes"`
+	Priority              *int            `json:"priority"`
+}
+
+// ScrapeWindow selects how far back a scrape goes, by default only jobs posted today are scraped.
+// At most one of the options can be set.
+type ScrapeWindow struct {
+	// Days before today to include
+	LookbackDays int `query:"lookback_days" json:"lookback_days"`
+	// Oldest posting date to include, YYYY-MM-DD
+	Since string `query:"since" json:"since"`
+	// Ignore posting dates and walk every page
+	Backfill bool `query:"backfill" json:"backfill"`
+}
+
+type ScrapeCompanyRequest struct {
+	ScrapeWindow
+}
+
+// PreviewCompanyRequest holds the preview options, the rest of the body is the add-company
+// payload of the provider, or careers_url when the provider is "auto"
+type PreviewCompanyRequest struct {
+	ScrapeWindow
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+	Limit    int    `json:"limit"`
 }
@@ -26,10 +26,101 @@ type JobSearchResponse struct {
 }
 
 type CompanyResponse struct {
-	Name                 string `json:"name"`
-	BaseUrl              string `json:"base_url"`
-	CareerSiteType       string `json:"career_site_type"`
-	ApiRequestBody       string `json:"api_request_body"`
-	ApiRequestQueryParam string `json:"api_request_query_param"`
-	ToScrape             bool   `json:"to_scrape"`
+	Name                  string  `json:"name"`
+	BaseUrl               string  `json:"base_url"`
+	CareerSiteType        string  `json:"career_site_type"`
+	ApiRequestBody        string  `json:"api_request_body"`
+	ApiRequestQueryParam  string  `json:"api_request_query_param"`
+	ToScrape              bool    `json:"to_scrape"`
+	ScrapeIntervalMinutes int     `json:"scrape_interval_minutes"`
+	Priority              int     `json:"priority"`
+	LastScrapedAt         *string `json:"last_scraped_at"`
+	Healthy               bool    `json:"healthy"`
+	UnhealthySince        *string `json:"unhealthy_since"`
+	LastSuccessAt         *string `json:"last_success_at"`
+	LastError             string  `json:"last_error"`
+	ConsecutiveFailures   int     `json:"consecutive_failures"`
+	LastJobCount          int     `json:"last_job_count"`
+}
+
+type ScrapeRunCompanyResponse struct {
+	CompanyName    string  `json:"company_name"`
+	CareerSiteType string  `json:"career_site_type"`
+	Status         string  `json:"status"`
+	FinishedAt     *string `json:"finished_at"`
+	ScrapeSince    *string `json:"scrape_since"`
+	JobsListed     int     `json:"jobs_listed"`
+	JobsInserted   int     `json:"jobs_inserted"`
+	JobsDuplicate  int     `json:"jobs_duplicate"`
+	JobsFailed     int     `json:"jobs_failed"`
+	JobsSeen       int     `json:"jobs_seen"`
+	Error          string  `json:"error"`
+}
+
+type ScrapeRunResponse struct {
+	ID              uint                       `json:"id"`
+	Status          string                     `json:"status"`
+	Trigger         string                     `json:"trigger"`
+	StartedAt       string                     `json:"started_at"`
+	FinishedAt      *string                    `json:"finished_at"`
+	ScrapeSince     *string                    `json:"scrape_since"`
+	CompaniesTotal  int                        `json:"companies_total"`
+	CompaniesFailed int                        `json:"companies_failed"`
+	JobsListed      int                        `json:"jobs_listed"`
+	JobsInserted    int                        `json:"jobs_inserted"`
+	JobsDuplicate   int                        `json:"jobs_duplicate"`
+	JobsFailed      int                        `json:"jobs_failed"`
+	Companies       []ScrapeRunCompanyResponse `json:"companies,omitempty"`
+}
+
+type ScrapeRunListResponse struct {
+	Runs    []ScrapeRunResponse `json:"runs"`
+	Total   int64               `json:"total"`
+	Limit   int                 `json:"limit"`
+	Offset  int                 `json:"offset"`
+	HasMore bool                `json:"has_more"`
+}
+
+// ScrapeRunEvent is a progress event of a running scrape run, streamed as SSE
+type ScrapeRunEvent struct {
+	Type        string             `json:"type"`
+	RunID       uint               `json:"run_id"`
+	Time        string             `json:"time"`
+	CompanyName string             `json:"company_name,omitempty"`
+	Page        int                `json:"page,omitempty"`
+	Jobs        *int               `json:"jobs,omitempty"`
+	JobRole     string             `json:"job_role,omitempty"`
+	JobLink     string             `json:"job_link,omitempty"`
+	Status      string             `json:"status,omitempty"`
+	Error       string             `json:"error,omitempty"`
+	Run         *ScrapeRunResponse `json:"run,omitempty"`
+}
+
+type ScheduledJobResponse struct {
+	Name       string  `json:"name"`
+	Schedule   string  `json:"schedule"`
+	NextRun    *string `json:"next_run"`
+	LastRun    *string `json:"last_run"`
+	LastResult string  `json:"last_result"`
+}
+
+type CompanyPreviewTimings struct {
+	ListingMs  int64  `json:"listing_ms"`
+	FirstJobMs *int64 `json:"first_job_ms"`
+	TotalMs    int64  `json:"total_ms"`
+}
+
+type CompanyPreviewResponse struct {
+	CareerSiteType string                `json:"career_site_type"`
+	BaseUrl        string                `json:"base_url"`
+	ApiRequestBody string                `json:"api_request_body"`
+	ScrapeSince    *string               `json:"scrape_since"`
+	Jobs           []JobResponse         `json:"jobs"`
+	Truncated      bool                  `json:"truncated"` // The limit was reached, the scrape stopped early
+	PagesFetched   int                   `json:"pages_fetched"`
+	JobsListed     int                   `json:"jobs_listed"`
+	JobsFailed     int                   `json:"jobs_failed"`
+	Warnings       []string              `json:"warnings"`
+	Error          string                `json:"error"`
+	Timings        CompanyPreviewTimings `json:"timings"`
 }
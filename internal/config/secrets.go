@@ -2,6 +2,7 @@ package config
 
 import (
 	"sync"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -10,6 +11,21 @@ import (
 
 type SecretsStruct struct {
 	DatabaseDSN string `env:"database_dsn"`
+	// Cron expressions of the built-in scheduler, a job with no expression isn't scheduled
+	ScrapeSchedule  string `env:"scrape_schedule"`
+	CleanupSchedule string `env:"cleanup_schedule"`
+	// Days before today a scheduled scrape goes back at most, however long ago the company was
+	// last scraped. 0 doesn't cap it.
+	ScheduledLookbackMaxDays int `env:"scheduled_lookback_max_days" envDefault:"14"`
+	// Time given to scrape runs in progress to finish on shutdown, they're interrupted after it
+	ShutdownTimeout time.Duration `env:"shutdown_timeout" envDefault:"5m"`
+	// A company is flagged unhealthy after this many failed scrapes in a row, 0 never flags it
+	UnhealthyAfterFailures int `env:"unhealthy_after_failures" envDefault:"3"`
+	// A company is flagged unhealthy when its listing comes back empty after one that had at least
+	// this many jobs, 0 never flags it
+	UnhealthyMinJobs int `env:"unhealthy_min_jobs" envDefault:"10"`
+	// Turn scraping off for companies flagged unhealthy
+	DisableUnhealthy bool `env:"disable_unhealthy" envDefault:"false"`
 }
 
 var (
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// Wait before listening again after the listening connection failed
+const listenRetryDelay = 5 * time.Second
+
+// Notify sends payload to every session listening on channel, across processes. Postgres caps
+// payloads at 8000 bytes.
+func Notify(channel, payload string) error {
+	return DB.Exec("SELECT pg_notify(?, ?)", channel, payload).Error
+}
+
+// Listen hands every payload notified on channel to handle until ctx is done. It keeps a
+// connection of its own out of the pool and listens again on a new one if it fails, notifications
+// sent in between are lost.
+func Listen(ctx context.Context, channel string, handle func(payload string)) {
+	for ctx.Err() == nil {
+		err := listen(ctx, channel, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("Stopped listening for notifications", "channel", channel, "error", err)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func listen(ctx context.Context, channel string, handle func(payload string)) error {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a connection: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected database driver %T", driverConn)
+		}
+		pgConn := stdlibConn.Conn()
+
+		if _, err := pgConn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", channel, err)
+		}
+		// The session is still listening, keep it out of the pool once done
+		defer pgConn.Close(context.Background())
+
+		for {
+			notification, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			handle(notification.Payload)
+		}
+	})
+}
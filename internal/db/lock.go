@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// AdvisoryLock is a session level Postgres advisory lock. It keeps its own connection so the
+// pool can't hand the locked session to other queries, and Postgres releases it by itself if
+// the process dies.
+type AdvisoryLock struct {
+	conn *sql.Conn
+	name string
+}
+
+// TryAdvisoryLock takes the advisory lock called name without waiting, ok is false when another
+// session, in this process or any other sharing the database, holds it
+func TryAdvisoryLock(ctx context.Context, name string) (lock *AdvisoryLock, ok bool, err error) {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get a connection: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", name).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to take advisory lock %s: %w", name, err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return &AdvisoryLock{conn: conn, name: name}, true, nil
+}
+
+// Release unlocks the lock and gives the connection back to the pool
+func (l *AdvisoryLock) Release() error {
+	_, err := l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", l.name)
+	if err != nil {
+		// Never pool a session that may still hold the lock, a bad connection gets discarded
+		l.conn.Raw(func(any) error { return driver.ErrBadConn })
+		l.conn.Close()
+		return fmt.Errorf("failed to release advisory lock %s: %w", l.name, err)
+	}
+	return l.conn.Close()
+}
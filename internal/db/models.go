@@ -4,12 +4,21 @@ import "time"
 
 // Companies to be scraped.
 type Companies struct {
-	Name                 string `gorm:"type:string;primaryKey"`
-	BaseUrl              string `gorm:"type:string;not null"`
-	CareerSiteType       string `gorm:"type:string;not null"` // Ex: Workday
-	ApiRequestBody       string `gorm:"type:string"`
-	ApiRequestQueryParam string `gorm:"type:string"`
-	ToScrape             bool   `gorm:"type:boolean"`
+	Name                  string     `gorm:"type:string;primaryKey"`
+	BaseUrl               string     `gorm:"type:string;not null"`
+	CareerSiteType        string     `gorm:"type:string;not null"` // Ex: Workday
+	ApiRequestBody        string     `gorm:"type:string"`
+	ApiRequestQueryParam  string     `gorm:"type:string"`
+	ToScrape              bool       `gorm:"type:boolean"`
+	ScrapeIntervalMinutes int        `gorm:"type:integer;not null;default:1440"` // Minutes between scheduled scrapes
+	Priority              int        `gorm:"type:integer;not null;default:0"`    // Higher is started first when due
+	LastScrapedAt         *time.Time `gorm:"type:timestamptz"`                   // Start of the last finished scrape
+	// Health, updated by every scrape of the company that isn't cancelled
+	LastSuccessAt       *time.Time `gorm:"type:timestamptz"`                // End of the last scrape whose listing succeeded
+	LastError           string     `gorm:"type:text"`                       // Listing error of the last failed scrape, cleared by a success
+	ConsecutiveFailures int        `gorm:"type:integer;not null;default:0"` // Failed scrapes since the last success
+	LastJobCount        int        `gorm:"type:integer;not null;default:0"` // Jobs on the listing pages of the last successful scrape
+	UnhealthySince      *time.Time `gorm:"type:timestamptz"`                // Nil while the company is healthy
 }
 
 type Jobs struct {
@@ -24,3 +33,88 @@ type Jobs struct {
 	CompanyName   string    `gorm:"type:string;not null;index:idx_company_name"` // Foreign key to Companies.Name
 	Company       Companies `gorm:"foreignKey:CompanyName;references:Name;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 }
+
+// Scrape run statuses
+const (
+	ScrapeRunRunning   = "running"
+	ScrapeRunSucceeded = "succeeded"
+	ScrapeRunPartial   = "partial" // Some companies failed
+	ScrapeRunFailed    = "failed"
+	ScrapeRunCancelled = "cancelled"
+)
+
+// What started a scrape run
+const (
+	ScrapeTriggerAPI      = "api"
+	ScrapeTriggerSchedule = "schedule"
+	ScrapeTriggerCompany  = "company" // Single company scrape
+)
+
+// ScrapeRun is one scrape session over the companies enabled for scraping.
+type ScrapeRun struct {
+	ID              uint               `gorm:"primaryKey"`
+	Status          string             `gorm:"type:string;not null;index:idx_scrape_run_status"`
+	Trigger         string             `gorm:"type:string;not null"`
+	StartedAt       time.Time          `gorm:"type:timestamptz;not null;index:idx_scrape_run_started_at"`
+	FinishedAt      *time.Time         `gorm:"type:timestamptz"`
+	ScrapeSince     *time.Time         `gorm:"type:timestamptz"` // Oldest posting date scraped, nil for a backfill
+	CompaniesTotal  int                `gorm:"not null;default:0"`
+	CompaniesFailed int                `gorm:"not null;default:0"`
+	JobsListed      int                `gorm:"not null;default:0"`
+	JobsInserted    int                `gorm:"not null;default:0"`
+	JobsDuplicate   int                `gorm:"not null;default:0"`
+	JobsFailed      int                `gorm:"not null;default:0"`
+	Companies       []ScrapeRunCompany `gorm:"foreignKey:ScrapeRunID;constraint:OnDelete:CASCADE"`
+}
+
+// ScrapeRunCompany is the outcome of one company within a scrape run.
+// Company names are copied rather than referenced so runs outlive deleted companies.
+type ScrapeRunCompany struct {
+	ID             uint       `gorm:"primaryKey"`
+	ScrapeRunID    uint       `gorm:"not null;index:idx_scrape_run_company_run"`
+	CompanyName    string     `gorm:"type:string;not null"`
+	CareerSiteType string     `gorm:"type:string;not null"`
+	Status         string     `gorm:"type:string;not null"`
+	FinishedAt     *time.Time `gorm:"type:timestamptz"`
+	ScrapeSince    *time.Time `gorm:"type:timestamptz"`   // Oldest posting date scraped, nil for a backfill
+	JobsListed     int        `gorm:"not null;default:0"` // Jobs found within the scrape window
+	JobsInserted   int        `gorm:"not null;default:0"`
+	JobsDuplicate  int        `gorm:"not null;default:0"` // Jobs already stored by an earlier run
+	JobsFailed     int        `gorm:"not null;default:0"`
+	JobsSeen       int        `gorm:"not null;default:0"` // Jobs on the listing pages fetched, whatever their date
+	Error          string     `gorm:"type:text"`          // Listing error, or the last job error
+}
+
+// Scrape task kinds
+const (
+	ScrapeTaskList   = "list"   // List a company's jobs
+	ScrapeTaskDetail = "detail" // Fetch the details of a listed job
+)
+
+// Scrape task statuses
+const (
+	ScrapeTaskPending   = "pending"
+	ScrapeTaskRunning   = "running"
+	ScrapeTaskDone      = "done"
+	ScrapeTaskDead      = "dead" // Out of attempts, kept for inspection
+	ScrapeTaskCancelled = "cancelled"
+)
+
+// ScrapeTask is a unit of scrape work of a run queued in Postgres, so pending work survives a
+// restart. A running task is invisible to claims until its AvailableAt, a task still running
+// after that is taken to be lost and claimed again.
+type ScrapeTask struct {
+	ID             uint      `gorm:"primaryKey"`
+	ScrapeRunID    uint      `gorm:"not null;index:idx_scrape_task_claim,priority:1;uniqueIndex:idx_scrape_task_key,priority:1"`
+	Kind           string    `gorm:"type:string;not null;index:idx_scrape_task_claim,priority:2"`
+	CareerSiteType string    `gorm:"type:string;not null;index:idx_scrape_task_claim,priority:3"`
+	Status         string    `gorm:"type:string;not null;index:idx_scrape_task_claim,priority:4"`
+	Key            string    `gorm:"type:string;not null;uniqueIndex:idx_scrape_task_key,priority:2"` // Identifies the work within the run, queueing it twice is a no-op
+	CompanyName    string    `gorm:"type:string;not null"`
+	Payload        string    `gorm:"type:jsonb;not null"` // The company to list, or the listed job
+	Attempts       int       `gorm:"not null;default:0"`
+	AvailableAt    time.Time `gorm:"type:timestamptz;not null;default:now()"`
+	LastError      string    `gorm:"type:text"`
+	CreatedAt      time.Time `gorm:"type:timestamptz"`
+	UpdatedAt      time.Time `gorm:"type:timestamptz"`
+}
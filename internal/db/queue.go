@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueScrapeTasks queues tasks, a task whose key is already queued for its run is skipped
+func EnqueueScrapeTasks(tasks []ScrapeTask) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	return DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&tasks).Error
+}
+
+// ClaimScrapeTasks claims up to limit available tasks of a run's provider, oldest first. Claimed
+// tasks are running, count an attempt and stay invisible to other claims for visibility. Tasks
+// locked by a concurrent claim are skipped rather than waited on.
+func ClaimScrapeTasks(ctx context.Context, runID uint, kind, careerSiteType string, limit int, visibility time.Duration) ([]ScrapeTask, error) {
+	var tasks []ScrapeTask
+	err := DB.WithContext(ctx).Raw(`
+		UPDATE scrape_tasks
+		SET status = ?, attempts = attempts + 1, available_at = now() + make_interval(secs => ?), updated_at = now()
+		WHERE id IN (
+			SELECT id FROM scrape_tasks
+			WHERE scrape_run_id = ? AND kind = ? AND career_site_type = ?
+				AND status IN (?, ?) AND available_at <= now()
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		ScrapeTaskRunning, visibility.Seconds(),
+		runID, kind, careerSiteType,
+		ScrapeTaskPending, ScrapeTaskRunning,
+		limit,
+	).Scan(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING doesn't keep the order of the subquery
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks, nil
+}
+
+// ExtendScrapeTask keeps a running task invisible to claims for visibility from now
+func ExtendScrapeTask(id uint, visibility time.Duration) error {
+	return DB.Exec(`UPDATE scrape_tasks SET available_at = now() + make_interval(secs => ?), updated_at = now()
+		WHERE id = ? AND status = ?`, visibility.Seconds(), id, ScrapeTaskRunning).Error
+}
+
+// The functions recording the outcome of a claimed task leave it alone once it's no longer
+// running, like a task closed by the cancellation of its run while a worker was on it
+
+// CompleteScrapeTask marks a running task done
+func CompleteScrapeTask(id uint) error {
+	return DB.Exec(`UPDATE scrape_tasks SET status = ?, updated_at = now() WHERE id = ? AND status = ?`,
+		ScrapeTaskDone, id, ScrapeTaskRunning).Error
+}
+
+// RetryScrapeTask puts a failed running task back in the queue, claimable after delay
+func RetryScrapeTask(id uint, taskErr error, delay time.Duration) error {
+	return DB.Exec(`UPDATE scrape_tasks
+		SET status = ?, available_at = now() + make_interval(secs => ?), last_error = ?, updated_at = now()
+		WHERE id = ? AND status = ?`, ScrapeTaskPending, delay.Seconds(), taskErr.Error(), id, ScrapeTaskRunning).Error
+}
+
+// DeadLetterScrapeTask marks a running task out of attempts, it stays in the table with its last
+// error
+func DeadLetterScrapeTask(id uint, taskErr error) error {
+	return DB.Exec(`UPDATE scrape_tasks SET status = ?, last_error = ?, updated_at = now() WHERE id = ? AND status = ?`,
+		ScrapeTaskDead, taskErr.Error(), id, ScrapeTaskRunning).Error
+}
+
+// CancelScrapeTask marks a running task cancelled along with its run, false if it wasn't running
+func CancelScrapeTask(id uint, reason string) (bool, error) {
+	result := DB.Exec(`UPDATE scrape_tasks SET status = ?, last_error = ?, updated_at = now() WHERE id = ? AND status = ?`,
+		ScrapeTaskCancelled, reason, id, ScrapeTaskRunning)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReleaseScrapeTask puts a task interrupted before it could finish back in the queue, claimable
+// straight away and without counting the attempt
+func ReleaseScrapeTask(id uint) error {
+	return DB.Exec(`UPDATE scrape_tasks
+		SET status = ?, attempts = greatest(attempts - 1, 0), available_at = now(), updated_at = now()
+		WHERE id = ? AND status = ?`, ScrapeTaskPending, id, ScrapeTaskRunning).Error
+}
+
+// CloseOpenScrapeTasks gives the pending and running tasks of a run's provider a final status and
+// returns them
+func CloseOpenScrapeTasks(runID uint, careerSiteType, status, reason string) ([]ScrapeTask, error) {
+	var tasks []ScrapeTask
+	err := DB.Raw(`UPDATE scrape_tasks SET status = ?, last_error = ?, updated_at = now()
+		WHERE scrape_run_id = ? AND career_site_type = ? AND status IN (?, ?)
+		RETURNING *`,
+		status, reason, runID, careerSiteType, ScrapeTaskPending, ScrapeTaskRunning,
+	).Scan(&tasks).Error
+	return tasks, err
+}
+
+// CountOpenScrapeTasks counts the pending and running tasks of a run's provider, of any provider
+// when careerSiteType is empty and of any kind when kind is empty
+func CountOpenScrapeTasks(runID uint, careerSiteType, kind string) (int64, error) {
+	query := DB.Model(&ScrapeTask{}).
+		Where("scrape_run_id = ? AND status IN ?", runID, []string{ScrapeTaskPending, ScrapeTaskRunning})
+	if careerSiteType != "" {
+		query = query.Where("career_site_type = ?", careerSiteType)
+	}
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// DeleteFinishedScrapeTasks removes the done and cancelled tasks of a run, dead tasks are kept
+func DeleteFinishedScrapeTasks(runID uint) error {
+	return DB.Where("scrape_run_id = ? AND status IN ?", runID, []string{ScrapeTaskDone, ScrapeTaskCancelled}).
+		Delete(&ScrapeTask{}).Error
+}
@@ -0,0 +1,192 @@
+package ashby
+
+import (
+	"context"
+	"fmt"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper/common"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/k3a/html2text"
+	"resty.dev/v3"
+)
+
+type AshbyCompensation struct {
+	CompensationTierSummary             string `json:"compensationTierSummary"`
+	ScrapeableCompensationSalarySummary string `json:"scrapeableCompensationSalarySummary"`
+}
+
+type AshbyJob struct {
+	ID              string            `json:"id"`
+	Title           string            `json:"title"`
+	Department      string            `json:"department"`
+	Team            string            `json:"team"`
+	EmploymentType  string            `json:"employmentType"`
+	Location        string            `json:"location"`
+	IsRemote        bool              `json:"isRemote"`
+	IsListed        bool              `json:"isListed"`
+	PublishedAt     string            `json:"publishedAt"`
+	JobUrl          string            `json:"jobUrl"`
+	ApplyUrl        string            `json:"applyUrl"`
+	DescriptionHtml string            `json:"descriptionHtml"`
+	Compensation    AshbyCompensation `json:"compensation"`
+}
+
+type AshbyJobBoardResponse struct {
+	ApiVersion string     `json:"apiVersion"`
+	Jobs       []AshbyJob `json:"jobs"`
+}
+
+type AshbyScraper struct{}
+
+// BuildJobBoardURL returns the Ashby posting API URL for a job board
+// Example: ramp -> https://api.ashbyhq.com/posting-api/job-board/ramp
+func BuildJobBoardURL(board string) string {
+	return "https://api.ashbyhq.com/posting-api/job-board/" + strings.Trim(strings.TrimSpace(board), "/")
+}
+
+// TransformBoardURLToAPIURL converts an Ashby job board URL to the posting API URL
+// Example: https://jobs.ashbyhq.com/ramp -> https://api.ashbyhq.com/posting-api/job-board/ramp
+func TransformBoardURLToAPIURL(boardURL string) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(boardURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	pathParts := common.SplitURLPath(parsedURL.Path)
+
+	var board string
+	switch strings.ToLower(parsedURL.Host) {
+	case "jobs.ashbyhq.com":
+		// Format: https://jobs.ashbyhq.com/{board}/{job_id}
+		if len(pathParts) > 0 {
+			board = pathParts[0]
+		}
+	case "api.ashbyhq.com":
+		// Format: https://api.ashbyhq.com/posting-api/job-board/{board}
+		if len(pathParts) >= 3 && pathParts[1] == "job-board" {
+			board = pathParts[2]
+		}
+	default:
+		return "", fmt.Errorf("not an Ashby job board URL: %s", parsedURL.Host)
+	}
+
+	if board == "" {
+		return "", fmt.Errorf("could not extract board name from URL path")
+	}
+
+	return BuildJobBoardURL(board), nil
+}
+
+func parseAshbyDate(dateStr string) (time.Time, error) {
+	// Parse ISO 8601 format: "2025-10-29T16:21:55.393+00:00"
+	return time.Parse(time.RFC3339Nano, dateStr)
+}
+
+// buildAshbyDescription converts the HTML description to text and appends the
+// compensation summary, which Ashby keeps outside the description
+func buildAshbyDescription(job AshbyJob) string {
+	description := common.RemoveExtraNewlines(common.CleanUTF8String(html2text.HTML2Text(job.DescriptionHtml)))
+
+	compensation := job.Compensation.CompensationTierSummary
+	if compensation == "" {
+		compensation = job.Compensation.ScrapeableCompensationSalarySummary
+	}
+	if compensation != "" {
+		description += "\n\nCompensation: " + common.CleanUTF8String(compensation)
+	}
+
+	return description
+}
+
+func listAndScrapeJobs(ctx context.Context, company db.Companies, scrapeDateLimitTruncated time.Time, sink common.ScrapeSink) (int, error) {
+	rClient := resty.New()
+	rClient.SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	defer rClient.Close()
+
+	// BaseUrl should be in format: https://api.ashbyhq.com/posting-api/job-board/{board}
+	if company.BaseUrl == "" {
+		return 0, fmt.Errorf("base URL not found for company")
+	}
+
+	var jobBoardResp AshbyJobBoardResponse
+
+	resp, err := rClient.R().
+		SetContext(ctx).
+		SetHeaders(map[string]string{
+			"Accept":        "application/json",
+			"cache-control": "no-cache",
+		}).
+		SetQueryParam("includeCompensation", "true").
+		SetResult(&jobBoardResp).
+		Get(company.BaseUrl)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	if resp.IsError() {
+		return 0, fmt.Errorf("ashby returned status %d", resp.StatusCode())
+	}
+
+	result := resp.Result().(*AshbyJobBoardResponse)
+	// Ashby returns the whole board in one response
+	sink.PageFetched(company, 1, len(result.Jobs))
+
+	slog.Info("[Ashby_Scraper] Successfully fetched jobs", "company", company.Name, "total_jobs", len(result.Jobs))
+
+	recentJobsCount := 0
+	for _, jobItem := range result.Jobs {
+		if !jobItem.IsListed {
+			continue
+		}
+
+		publishedTime, err := parseAshbyDate(jobItem.PublishedAt)
+		if err != nil {
+			slog.Error("[Ashby_Scraper] Failed to parse publishedAt date", "date", jobItem.PublishedAt, "error", err)
+			sink.Warning(company, fmt.Sprintf("skipped job %s: failed to parse publishedAt %q", jobItem.JobUrl, jobItem.PublishedAt))
+			continue
+		}
+
+		// Check if we should scrape this job using centralized function
+		if !common.ShouldScrapeJob(publishedTime, scrapeDateLimitTruncated) {
+			continue
+		}
+		recentJobsCount++
+
+		job := &db.Jobs{
+			JobHash:      common.GetSHA256Hash(jobItem.JobUrl),
+			JobId:        jobItem.ID,
+			JobRole:      jobItem.Title,
+			JobDetails:   buildAshbyDescription(jobItem),
+			JobPostDate:  publishedTime.Format("2006-01-02"),
+			JobLink:      jobItem.JobUrl,
+			JobAISummary: "",
+			CompanyName:  company.Name,
+		}
+
+		// The listing already carries description and compensation, so there is
+		// no separate detail worker step for Ashby
+		sink.JobScraped(job)
+	}
+
+	slog.Info("[Ashby_Scraper] Recent jobs scraped",
+		"company", company.Name,
+		"recent_jobs", recentJobsCount,
+		"total_jobs", len(result.Jobs))
+
+	return recentJobsCount, nil
+}
+
+func (as AshbyScraper) ListJobs(ctx context.Context, company db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) (int, error) {
+	// Get date at midnight using centralized function
+	return listAndScrapeJobs(ctx, company, common.GetDateMidnight(scrapeDayLimit), sink)
+}
+
+// ScrapeJob is never called, the listing carries every job's details so no job is queued
+func (as AshbyScraper) ScrapeJob(ctx context.Context, queued common.QueuedJob, scrapeDayLimit time.Time, sink common.ScrapeSink) error {
+	return fmt.Errorf("ashby jobs have no details request")
+}
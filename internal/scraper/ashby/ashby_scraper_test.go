@@ -0,0 +1,200 @@
+package ashby
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Note: GetSHA256Hash, CleanUTF8String, and RemoveExtraNewlines tests
+// are in job-scraper/internal/scraper/common/utils_test.go
+// This test file only contains Ashby-specific function tests.
+
+func TestBuildJobBoardURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    string
+		expected string
+	}{
+		{
+			name:     "Plain board name",
+			board:    "ramp",
+			expected: "https://api.ashbyhq.com/posting-api/job-board/ramp",
+		},
+		{
+			name:     "Board name with whitespace and slashes",
+			board:    " /ramp/ ",
+			expected: "https://api.ashbyhq.com/posting-api/job-board/ramp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BuildJobBoardURL(tt.board)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseAshbyDate(t *testing.T) {
+	tests := []struct {
+		name        string
+		dateStr     string
+		expectError bool
+		expected    time.Time
+	}{
+		{
+			name:        "Fractional seconds with offset",
+			dateStr:     "2025-10-29T16:21:55.393+00:00",
+			expectError: false,
+			expected:    time.Date(2025, 10, 29, 16, 21, 55, 393000000, time.UTC),
+		},
+		{
+			name:        "No fractional seconds",
+			dateStr:     "2025-11-06T14:30:00Z",
+			expectError: false,
+			expected:    time.Date(2025, 11, 6, 14, 30, 0, 0, time.UTC),
+		},
+		{
+			name:        "Invalid date format",
+			dateStr:     "not-a-date",
+			expectError: true,
+		},
+		{
+			name:        "Empty string",
+			dateStr:     "",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseAshbyDate(tt.dateStr)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildAshbyDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		job      AshbyJob
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "Description with tier summary",
+			job: AshbyJob{
+				DescriptionHtml: "<p>Build payments.</p>",
+				Compensation: AshbyCompensation{
+					CompensationTierSummary:             "$150K – $200K • Offers Equity",
+					ScrapeableCompensationSalarySummary: "$150K - $200K",
+				},
+			},
+			contains: []string{"Build payments.", "Compensation: $150K – $200K • Offers Equity"},
+			excludes: []string{"<p>"},
+		},
+		{
+			name: "Falls back to salary summary",
+			job: AshbyJob{
+				DescriptionHtml: "<p>Build payments.</p>",
+				Compensation: AshbyCompensation{
+					ScrapeableCompensationSalarySummary: "$150K - $200K",
+				},
+			},
+			contains: []string{"Compensation: $150K - $200K"},
+		},
+		{
+			name: "No compensation",
+			job: AshbyJob{
+				DescriptionHtml: "<p>Build payments.</p>",
+			},
+			contains: []string{"Build payments."},
+			excludes: []string{"Compensation:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildAshbyDescription(tt.job)
+			for _, expected := range tt.contains {
+				if !strings.Contains(result, expected) {
+					t.Errorf("Expected description to contain %q, got %q", expected, result)
+				}
+			}
+			for _, unexpected := range tt.excludes {
+				if strings.Contains(result, unexpected) {
+					t.Errorf("Expected description not to contain %q, got %q", unexpected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestTransformBoardURLToAPIURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		boardURL    string
+		expectedURL string
+		expectError bool
+	}{
+		{
+			name:        "Job board URL",
+			boardURL:    "https://jobs.ashbyhq.com/ramp",
+			expectedURL: "https://api.ashbyhq.com/posting-api/job-board/ramp",
+		},
+		{
+			name:        "Job URL",
+			boardURL:    "https://jobs.ashbyhq.com/ramp/6f1c2b2e-1111-2222-3333-444455556666",
+			expectedURL: "https://api.ashbyhq.com/posting-api/job-board/ramp",
+		},
+		{
+			name:        "Already an API URL",
+			boardURL:    "https://api.ashbyhq.com/posting-api/job-board/ramp?includeCompensation=true",
+			expectedURL: "https://api.ashbyhq.com/posting-api/job-board/ramp",
+		},
+		{
+			name:        "Missing board",
+			boardURL:    "https://jobs.ashbyhq.com",
+			expectError: true,
+		},
+		{
+			name:        "Not an Ashby URL",
+			boardURL:    "https://jobs.lever.co/ramp",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TransformBoardURLToAPIURL(tt.boardURL)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if result != tt.expectedURL {
+				t.Errorf("Expected %q, got %q", tt.expectedURL, result)
+			}
+		})
+	}
+}
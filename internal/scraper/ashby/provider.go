@@ -0,0 +1,41 @@
+package ashby
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/scraper/common"
+	"job-scraper/internal/types"
+)
+
+func init() {
+	scraper.Register(scraper.Provider{
+		Name:            types.Ashby,
+		New:             func() scraper.Scraper { return AshbyScraper{} },
+		ValidatePayload: validatePayload,
+		NormaliseURL:    normaliseURL,
+		MatchesURL:      common.HostIs("jobs.ashbyhq.com", "api.ashbyhq.com"),
+		URLPatterns:     "host is jobs.ashbyhq.com or api.ashbyhq.com",
+	})
+}
+
+func normaliseURL(boardURL string) (scraper.CompanySource, error) {
+	apiURL, err := TransformBoardURLToAPIURL(boardURL)
+	if err != nil {
+		return scraper.CompanySource{}, err
+	}
+	return scraper.CompanySource{BaseUrl: apiURL}, nil
+}
+
+func validatePayload(payload []byte) (scraper.CompanySource, error) {
+	var ashbyCompData api_models.AddAshbyCompanyScrapeList
+	if err := json.Unmarshal(payload, &ashbyCompData); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	if ashbyCompData.Board == "" {
+		return scraper.CompanySource{}, fmt.Errorf("board is required")
+	}
+	return scraper.CompanySource{BaseUrl: BuildJobBoardURL(ashbyCompData.Board)}, nil
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/db"
+)
+
+// ScrapeSink receives everything a scraper produces during a run.
+// Scrapers call it from several goroutines at once, so implementations must be safe for
+// concurrent use.
+type ScrapeSink interface {
+	// CompanyStarted reports the start of a company's listing stage
+	CompanyStarted(company db.Companies)
+	// PageFetched reports a fetched page of a company's job listing, pages count from 1.
+	// jobsOnPage is the number of jobs on the page before the scrape window is applied.
+	PageFetched(company db.Companies, page int, jobsOnPage int)
+	// JobQueued hands a listed job to the detail stage, the sink owns the job from then on.
+	// detail is what the provider's ScrapeJob needs besides the company and the job, it must
+	// survive a JSON round trip since queued jobs may be stored.
+	JobQueued(company db.Companies, job *db.Jobs, detail any)
+	// JobScraped receives a job with its details filled in, ready to be stored
+	JobScraped(job *db.Jobs)
+	// JobFailed reports a listed job whose details couldn't be fetched, for good
+	JobFailed(job *db.Jobs, err error)
+	// Warning reports a problem that didn't stop the company's scrape, like a listed job that
+	// was skipped because its data couldn't be parsed
+	Warning(company db.Companies, message string)
+	// CompanyListed reports the end of a company's listing stage with the number of jobs
+	// found within the scrape window. err is set when listing failed or was cancelled.
+	CompanyListed(company db.Companies, jobsFound int, err error)
+}
+
+// QueuedJob is a listed job waiting for its details, with the company it belongs to and the
+// provider's detail request
+type QueuedJob struct {
+	Company db.Companies    `json:"company"`
+	Job     *db.Jobs        `json:"job"`
+	Detail  json.RawMessage `json:"detail,omitempty"`
+}
+
+// NewQueuedJob builds the queued job a sink receives through JobQueued
+func NewQueuedJob(company db.Companies, job *db.Jobs, detail any) (QueuedJob, error) {
+	queued := QueuedJob{Company: company, Job: job}
+	if detail != nil {
+		encoded, err := json.Marshal(detail)
+		if err != nil {
+			return QueuedJob{}, fmt.Errorf("failed to encode job detail request: %w", err)
+		}
+		queued.Detail = encoded
+	}
+	return queued, nil
+}
+
+// DecodeDetail decodes the provider's detail request into v
+func (q QueuedJob) DecodeDetail(v any) error {
+	if err := json.Unmarshal(q.Detail, v); err != nil {
+		return fmt.Errorf("invalid job detail request: %w", err)
+	}
+	return nil
+}
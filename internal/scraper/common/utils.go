@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"job-scraper/internal/db"
 	"log/slog"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -42,6 +43,13 @@ func RemoveExtraNewlines(s string) string {
 // InsertJobToDB inserts a job into the database if it doesn't already exist
 // Returns true if the job was inserted, false if it already existed
 func InsertJobToDB(job *db.Jobs, scraperName string) bool {
+	inserted, _ := InsertJob(job, scraperName)
+	return inserted
+}
+
+// InsertJob inserts a job into the database if it doesn't already exist
+// Returns true if the job was inserted, false with a nil error if it already existed
+func InsertJob(job *db.Jobs, scraperName string) (bool, error) {
 	dbResult := db.DB.FirstOrCreate(job, db.Jobs{JobHash: job.JobHash})
 
 	if dbResult.Error != nil {
@@ -49,7 +57,7 @@ func InsertJobToDB(job *db.Jobs, scraperName string) bool {
 			"jobLink", job.JobLink,
 			"jobId", job.JobId,
 			"error", dbResult.Error)
-		return false
+		return false, dbResult.Error
 	}
 
 	if dbResult.RowsAffected > 0 {
@@ -57,13 +65,13 @@ func InsertJobToDB(job *db.Jobs, scraperName string) bool {
 			"jobLink", job.JobLink,
 			"jobId", job.JobId,
 			"company", job.CompanyName)
-		return true
+		return true, nil
 	}
 
 	slog.Debug("["+scraperName+"_Worker] Job already exists, skipping insert",
 		"jobLink", job.JobLink,
 		"jobId", job.JobId)
-	return false
+	return false, nil
 }
 
 // GetTodayMidnight returns today's date at midnight in local timezone
@@ -101,3 +109,41 @@ func IsJobFromToday(jobPostDate time.Time) bool {
 func ShouldScrapeJob(jobPostDate time.Time, scrapeDateLimit time.Time) bool {
 	return IsJobWithinScrapeLimit(jobPostDate, scrapeDateLimit)
 }
+
+// SplitURLPath splits a URL path into its non-empty segments
+// Example: "/en-US/site/job/" -> ["en-US", "site", "job"]
+func SplitURLPath(path string) []string {
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// HostIs returns a matcher for URLs whose host is one of hosts
+func HostIs(hosts ...string) func(u *url.URL) bool {
+	return func(u *url.URL) bool {
+		host := strings.ToLower(u.Host)
+		for _, h := range hosts {
+			if host == h {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HostHasSuffix returns a matcher for URLs whose host ends with one of suffixes
+func HostHasSuffix(suffixes ...string) func(u *url.URL) bool {
+	return func(u *url.URL) bool {
+		host := strings.ToLower(u.Host)
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -422,3 +422,30 @@ func TestShouldScrapeJob(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitURLPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected []string
+	}{
+		{name: "Leading and trailing slashes", path: "/en-US/site/job/", expected: []string{"en-US", "site", "job"}},
+		{name: "Repeated slashes", path: "//a//b", expected: []string{"a", "b"}},
+		{name: "Root", path: "/", expected: nil},
+		{name: "Empty", path: "", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SplitURLPath(tt.path)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("SplitURLPath(%q) = %v, want %v", tt.path, result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("SplitURLPath(%q) = %v, want %v", tt.path, result, tt.expected)
+				}
+			}
+		})
+	}
+}
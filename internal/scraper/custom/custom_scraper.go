@@ -0,0 +1,287 @@
+package custom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper/common"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/k3a/html2text"
+	"resty.dev/v3"
+)
+
+type CustomScraper struct{}
+
+// jobDetailRequest is what a queued job needs to fetch its details, the spec is the company's
+type jobDetailRequest struct {
+	Vars map[string]string `json:"vars"`
+}
+
+// Shared by every job details request, resty clients are safe for concurrent use
+var detailClient = resty.New().SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+
+func decodeJSON(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// Keep numeric ids intact instead of turning them into floats
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func cleanDescription(description string, descriptionFormat string) string {
+	if descriptionFormat == "html" {
+		description = html2text.HTML2Text(description)
+	}
+	return common.RemoveExtraNewlines(common.CleanUTF8String(description))
+}
+
+// buildJob maps one job object of the list response to a db.Jobs row using the spec's field paths.
+// The returned vars are used to render the detail request templates.
+func buildJob(spec *Spec, jobData any, companyName string) (*db.Jobs, time.Time, map[string]string, error) {
+	title := lookupString(jobData, spec.Fields.Title)
+	if title == "" {
+		return nil, time.Time{}, nil, fmt.Errorf("title not found at %q", spec.Fields.Title)
+	}
+
+	jobPostDate, err := parseDate(lookupString(jobData, spec.Fields.Date), spec.DateFormat)
+	if err != nil {
+		return nil, time.Time{}, nil, err
+	}
+
+	vars := map[string]string{
+		"id": lookupString(jobData, spec.Fields.ID),
+	}
+
+	link := lookupString(jobData, spec.Fields.Link)
+	if link == "" && spec.LinkTemplate != "" {
+		link = renderTemplate(spec.LinkTemplate, vars, url.PathEscape)
+	}
+	if link == "" {
+		return nil, time.Time{}, nil, fmt.Errorf("job link not found for %q", title)
+	}
+	vars["link"] = link
+
+	jobId := vars["id"]
+	if jobId == "" {
+		jobId = link
+	}
+
+	job := &db.Jobs{
+		JobHash:      common.GetSHA256Hash(link),
+		JobId:        jobId,
+		JobRole:      common.CleanUTF8String(title),
+		JobDetails:   cleanDescription(lookupString(jobData, spec.Fields.Description), spec.DescriptionFormat),
+		JobPostDate:  jobPostDate.Format("2006-01-02"),
+		JobLink:      link,
+		JobAISummary: "",
+		CompanyName:  companyName,
+	}
+
+	return job, jobPostDate, vars, nil
+}
+
+func doRequest(ctx context.Context, rClient *resty.Client, method, requestURL string, headers, queryParams map[string]string, body string) (any, error) {
+	req := rClient.R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetHeaders(headers).
+		SetQueryParams(queryParams)
+	if body != "" {
+		req.SetHeader("Content-Type", "application/json").SetBody(body)
+	}
+
+	resp, err := req.Execute(method, requestURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("request returned status %d", resp.StatusCode())
+	}
+
+	data, err := decodeJSON(resp.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON response: %w", err)
+	}
+	return data, nil
+}
+
+func listJobs(ctx context.Context, company db.Companies, scrapeDateLimitTruncated time.Time, sink common.ScrapeSink) (int, error) {
+	// The spec is stored in ApiRequestBody
+	spec, err := ParseSpec([]byte(company.ApiRequestBody))
+	if err != nil {
+		return 0, fmt.Errorf("invalid spec: %w", err)
+	}
+
+	rClient := resty.New()
+	rClient.SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	defer rClient.Close()
+
+	pagination := spec.Pagination
+	offset := 0
+	page := pagination.StartPage
+	cursor := ""
+	jobsFound := 0
+
+	for pageCount := 0; pageCount < pagination.MaxPages; pageCount++ {
+		if err := ctx.Err(); err != nil {
+			return jobsFound, err
+		}
+
+		vars := map[string]string{
+			"offset": strconv.Itoa(offset),
+			"limit":  strconv.Itoa(pagination.PageSize),
+			"page":   strconv.Itoa(page),
+			"cursor": cursor,
+		}
+
+		queryParams := make(map[string]string, len(spec.QueryParams))
+		for key, value := range spec.QueryParams {
+			queryParams[key] = renderTemplate(value, vars, nil)
+		}
+
+		data, err := doRequest(
+			ctx,
+			rClient,
+			spec.Method,
+			renderTemplate(spec.ListURL, vars, url.QueryEscape),
+			spec.Headers,
+			queryParams,
+			renderTemplate(spec.BodyTemplate, vars, nil),
+		)
+		if err != nil {
+			return jobsFound, fmt.Errorf("failed to fetch jobs on page %d: %w", pageCount, err)
+		}
+
+		jobsData, ok := lookupPath(data, spec.JobsPath)
+		jobList, isList := jobsData.([]any)
+		if !ok || !isList {
+			return jobsFound, fmt.Errorf("jobs_path %s did not resolve to a list", spec.JobsPath)
+		}
+
+		slog.Info("[Custom_Scraper] Successfully fetched jobs", "company", company.Name, "page", pageCount, "jobs_in_response", len(jobList))
+		sink.PageFetched(company, pageCount+1, len(jobList))
+
+		if len(jobList) == 0 {
+			slog.Info("[Custom_Scraper] No more jobs found, stopping pagination", "company", company.Name)
+			break
+		}
+
+		// Only jobs that mapped have a date, a page where none did says nothing about the window
+		jobsMapped, jobsInWindow := 0, 0
+		for _, jobData := range jobList {
+			job, jobPostDate, jobVars, err := buildJob(spec, jobData, company.Name)
+			if err != nil {
+				slog.Error("[Custom_Scraper] Failed to map job", "company", company.Name, "error", err)
+				sink.Warning(company, fmt.Sprintf("skipped job: failed to map job: %s", err))
+				continue
+			}
+			jobsMapped++
+
+			// Check if we should scrape this job using centralized function
+			if !common.ShouldScrapeJob(jobPostDate, scrapeDateLimitTruncated) {
+				continue
+			}
+			jobsInWindow++
+			jobsFound++
+
+			if spec.Detail != nil {
+				sink.JobQueued(company, job, jobDetailRequest{Vars: jobVars})
+				continue
+			}
+
+			sink.JobScraped(job)
+		}
+
+		if jobsMapped > 0 && jobsInWindow == 0 && pagination.SortedByDateDesc {
+			slog.Info("[Custom_Scraper] Full page outside scrape window, stopping pagination", "company", company.Name)
+			break
+		}
+
+		switch pagination.Style {
+		case PaginationNone:
+			return jobsFound, nil
+		case PaginationOffset:
+			offset += len(jobList)
+		case PaginationPage:
+			page++
+			offset += len(jobList)
+		case PaginationCursor:
+			cursor = lookupString(data, pagination.CursorPath)
+			if cursor == "" {
+				slog.Info("[Custom_Scraper] No next cursor, stopping pagination", "company", company.Name)
+				return jobsFound, nil
+			}
+		}
+
+		if pagination.TotalPath != "" {
+			total, err := strconv.Atoi(lookupString(data, pagination.TotalPath))
+			if err == nil && offset >= total {
+				slog.Info("[Custom_Scraper] Reached end of job listings", "company", company.Name)
+				return jobsFound, nil
+			}
+		}
+	}
+
+	return jobsFound, nil
+}
+
+func (cs CustomScraper) ListJobs(ctx context.Context, company db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) (int, error) {
+	// Get date at midnight using centralized function
+	return listJobs(ctx, company, common.GetDateMidnight(scrapeDayLimit), sink)
+}
+
+func (cs CustomScraper) ScrapeJob(ctx context.Context, queued common.QueuedJob, scrapeDayLimit time.Time, sink common.ScrapeSink) error {
+	// The spec is stored in ApiRequestBody
+	spec, err := ParseSpec([]byte(queued.Company.ApiRequestBody))
+	if err != nil {
+		return fmt.Errorf("invalid spec: %w", err)
+	}
+	if spec.Detail == nil {
+		return fmt.Errorf("spec has no detail request")
+	}
+
+	var req jobDetailRequest
+	if err := queued.DecodeDetail(&req); err != nil {
+		return err
+	}
+
+	detail := spec.Detail
+	job := queued.Job
+
+	data, err := doRequest(
+		ctx,
+		detailClient,
+		detail.Method,
+		renderURLTemplate(detail.URLTemplate, req.Vars),
+		detail.Headers,
+		nil,
+		renderTemplate(detail.BodyTemplate, req.Vars, nil),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fetch job details: %w", err)
+	}
+
+	job.JobDetails = cleanDescription(lookupString(data, detail.DescriptionPath), spec.DescriptionFormat)
+	if link := lookupString(data, detail.LinkPath); link != "" {
+		job.JobLink = link
+		job.JobHash = common.GetSHA256Hash(link)
+	}
+	if dateStr := lookupString(data, detail.DatePath); dateStr != "" {
+		if jobPostDate, err := parseDate(dateStr, spec.DateFormat); err == nil {
+			job.JobPostDate = jobPostDate.Format("2006-01-02")
+		}
+	}
+
+	sink.JobScraped(job)
+	return nil
+}
@@ -0,0 +1,89 @@
+package custom
+
+import (
+	"testing"
+)
+
+// Note: GetSHA256Hash, CleanUTF8String, and RemoveExtraNewlines tests
+// are in job-scraper/internal/scraper/common/utils_test.go
+// This test file only contains custom scraper function tests.
+
+func TestBuildJob(t *testing.T) {
+	spec, err := ParseSpec([]byte(`{
+		"list_url": "https://api.example.com/jobs",
+		"jobs_path": "$.jobs",
+		"fields": {"title": "$.name", "id": "$.id", "link": "$.urls.public", "date": "$.posted", "description": "$.body"},
+		"link_template": "https://example.com/careers/{{id}}",
+		"date_format": "2006-01-02"
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		jobJSON      string
+		expectError  bool
+		expectedLink string
+		expectedId   string
+		expectedBody string
+	}{
+		{
+			name:         "All fields present",
+			jobJSON:      `{"id":42,"name":"Engineer","urls":{"public":"https://example.com/jobs/engineer"},"posted":"2025-11-06","body":"<p>Write Go.</p>"}`,
+			expectedLink: "https://example.com/jobs/engineer",
+			expectedId:   "42",
+			expectedBody: "Write Go.",
+		},
+		{
+			name:         "Link built from template",
+			jobJSON:      `{"id":"a/b","name":"Engineer","posted":"2025-11-06"}`,
+			expectedLink: "https://example.com/careers/a%2Fb",
+			expectedId:   "a/b",
+			expectedBody: "",
+		},
+		{
+			name:        "Missing title",
+			jobJSON:     `{"id":42,"posted":"2025-11-06"}`,
+			expectError: true,
+		},
+		{
+			name:        "Invalid date",
+			jobJSON:     `{"id":42,"name":"Engineer","posted":"Nov 6"}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobData, err := decodeJSON([]byte(tt.jobJSON))
+			if err != nil {
+				t.Fatalf("Failed to decode job JSON: %v", err)
+			}
+
+			job, _, vars, err := buildJob(spec, jobData, "Example")
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if job.JobLink != tt.expectedLink || vars["link"] != tt.expectedLink {
+				t.Errorf("Expected link %q, got %q (vars %q)", tt.expectedLink, job.JobLink, vars["link"])
+			}
+			if job.JobId != tt.expectedId {
+				t.Errorf("Expected id %q, got %q", tt.expectedId, job.JobId)
+			}
+			if job.JobDetails != tt.expectedBody {
+				t.Errorf("Expected details %q, got %q", tt.expectedBody, job.JobDetails)
+			}
+			if job.JobPostDate != "2025-11-06" || job.CompanyName != "Example" || job.JobRole != "Engineer" {
+				t.Errorf("Unexpected job %+v", job)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package custom
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/types"
+)
+
+// Custom companies are described by a spec, there is no URL to detect them from
+func init() {
+	scraper.Register(scraper.Provider{
+		Name:                types.Custom,
+		New:                 func() scraper.Scraper { return CustomScraper{} },
+		ValidatePayload:     validatePayload,
+		ValidateRequestBody: validateSpec,
+	})
+}
+
+// validateSpec checks a spec, its list URL doubles as the base URL
+func validateSpec(body []byte) (scraper.CompanySource, error) {
+	spec, compactSpec, err := CompactSpec(body)
+	if err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid spec: %w", err)
+	}
+	return scraper.CompanySource{BaseUrl: spec.ListURL, ApiRequestBody: compactSpec}, nil
+}
+
+func validatePayload(payload []byte) (scraper.CompanySource, error) {
+	var customCompData api_models.AddCustomCompanyScrapeList
+	if err := json.Unmarshal(payload, &customCompData); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	// The declarative spec lives in ApiRequestBody
+	return validateSpec(customCompData.Spec)
+}
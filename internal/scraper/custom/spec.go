@@ -0,0 +1,321 @@
+package custom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pagination styles supported by the custom scraper
+const (
+	PaginationNone   = "none"
+	PaginationOffset = "offset"
+	PaginationPage   = "page"
+	PaginationCursor = "cursor"
+)
+
+// Spec describes how to scrape a JSON career API without writing Go.
+// It is stored compacted in Companies.ApiRequestBody, the list URL is also stored as BaseUrl.
+//
+// Templates (list_url, query_params values, body_template and the detail templates) can use
+// {{offset}}, {{limit}}, {{page}} and {{cursor}} for pagination, the detail templates can
+// also use {{id}} and {{link}} of the listed job.
+type Spec struct {
+	ListURL      string            `json:"list_url"`
+	Method       string            `json:"method"`
+	Headers      map[string]string `json:"headers"`
+	QueryParams  map[string]string `json:"query_params"`
+	BodyTemplate string            `json:"body_template"`
+	Pagination   PaginationSpec    `json:"pagination"`
+	// Path to the array of jobs in the list response, "$" when the response itself is the array
+	JobsPath string     `json:"jobs_path"`
+	Fields   FieldsSpec `json:"fields"`
+	// Optional template used to build the public job link when the API has no link field
+	LinkTemplate string `json:"link_template"`
+	// Go time layout, or one of "rfc3339", "unix", "unix_ms". Defaults to RFC 3339 / YYYY-MM-DD
+	DateFormat string `json:"date_format"`
+	// "html" (default) or "text"
+	DescriptionFormat string      `json:"description_format"`
+	Detail            *DetailSpec `json:"detail"`
+}
+
+type PaginationSpec struct {
+	Style    string `json:"style"`
+	PageSize int    `json:"page_size"`
+	// First page number for "page" pagination, usually 0 or 1
+	StartPage int `json:"start_page"`
+	// Path to the next cursor in the list response for "cursor" pagination
+	CursorPath string `json:"cursor_path"`
+	// Optional path to the total number of jobs, stops offset/page pagination early
+	TotalPath string `json:"total_path"`
+	MaxPages  int    `json:"max_pages"`
+	// Set when the API returns the newest jobs first, pagination then stops on the
+	// first page that is entirely outside the scrape window
+	SortedByDateDesc bool `json:"sorted_by_date_desc"`
+}
+
+// FieldsSpec maps job attributes to JSONPath-style paths relative to one job object
+type FieldsSpec struct {
+	Title       string `json:"title"`
+	ID          string `json:"id"`
+	Link        string `json:"link"`
+	Date        string `json:"date"`
+	Description string `json:"description"`
+}
+
+// DetailSpec describes an optional per-job request used to fetch the description
+type DetailSpec struct {
+	URLTemplate     string            `json:"url_template"`
+	Method          string            `json:"method"`
+	Headers         map[string]string `json:"headers"`
+	BodyTemplate    string            `json:"body_template"`
+	DescriptionPath string            `json:"description_path"`
+	// Optional paths to override the listed link and date from the detail response
+	LinkPath string `json:"link_path"`
+	DatePath string `json:"date_path"`
+}
+
+const (
+	defaultPageSize = 50
+	defaultMaxPages = 100
+)
+
+// ParseSpec decodes and validates a spec, filling in defaults
+func ParseSpec(raw []byte) (*Spec, error) {
+	var spec Spec
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+
+	if spec.ListURL == "" {
+		return nil, fmt.Errorf("list_url is required")
+	}
+	if parsedURL, err := url.Parse(spec.ListURL); err != nil || parsedURL.Host == "" {
+		return nil, fmt.Errorf("list_url must be an absolute URL")
+	}
+
+	spec.Method = strings.ToUpper(spec.Method)
+	if spec.Method == "" {
+		spec.Method = "GET"
+	}
+	if spec.Method != "GET" && spec.Method != "POST" {
+		return nil, fmt.Errorf("method must be GET or POST")
+	}
+
+	if spec.Pagination.Style == "" {
+		spec.Pagination.Style = PaginationNone
+	}
+	switch spec.Pagination.Style {
+	case PaginationNone, PaginationOffset, PaginationPage:
+	case PaginationCursor:
+		if spec.Pagination.CursorPath == "" {
+			return nil, fmt.Errorf("pagination.cursor_path is required for cursor pagination")
+		}
+	default:
+		return nil, fmt.Errorf("pagination.style must be one of none, offset, page, cursor")
+	}
+	if spec.Pagination.PageSize <= 0 {
+		spec.Pagination.PageSize = defaultPageSize
+	}
+	if spec.Pagination.MaxPages <= 0 {
+		spec.Pagination.MaxPages = defaultMaxPages
+	}
+
+	if spec.JobsPath == "" {
+		return nil, fmt.Errorf("jobs_path is required")
+	}
+	if spec.Fields.Title == "" {
+		return nil, fmt.Errorf("fields.title is required")
+	}
+	if spec.Fields.Link == "" && spec.LinkTemplate == "" {
+		return nil, fmt.Errorf("fields.link or link_template is required")
+	}
+	if spec.Fields.Date == "" {
+		return nil, fmt.Errorf("fields.date is required")
+	}
+
+	switch spec.DescriptionFormat {
+	case "":
+		spec.DescriptionFormat = "html"
+	case "html", "text":
+	default:
+		return nil, fmt.Errorf("description_format must be html or text")
+	}
+
+	if spec.Detail != nil {
+		if spec.Detail.URLTemplate == "" {
+			return nil, fmt.Errorf("detail.url_template is required when detail is set")
+		}
+		if spec.Detail.DescriptionPath == "" {
+			return nil, fmt.Errorf("detail.description_path is required when detail is set")
+		}
+		spec.Detail.Method = strings.ToUpper(spec.Detail.Method)
+		if spec.Detail.Method == "" {
+			spec.Detail.Method = "GET"
+		}
+		if spec.Detail.Method != "GET" && spec.Detail.Method != "POST" {
+			return nil, fmt.Errorf("detail.method must be GET or POST")
+		}
+	}
+
+	return &spec, nil
+}
+
+// CompactSpec validates a spec and returns it compacted for storage in ApiRequestBody
+func CompactSpec(raw json.RawMessage) (*Spec, string, error) {
+	spec, err := ParseSpec(raw)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var compactBuf bytes.Buffer
+	if err := json.Compact(&compactBuf, raw); err != nil {
+		return nil, "", fmt.Errorf("invalid spec JSON: %w", err)
+	}
+	return spec, compactBuf.String(), nil
+}
+
+// lookupPath resolves a JSONPath-style path such as "$.data.jobs[0].title" against decoded JSON.
+// Only child (.name) and index ([n]) steps are supported, the leading "$" is optional.
+func lookupPath(data any, path string) (any, bool) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	current := data
+
+	for path != "" {
+		switch {
+		case strings.HasPrefix(path, "."):
+			path = path[1:]
+		case strings.HasPrefix(path, "["):
+			end := strings.Index(path, "]")
+			if end == -1 {
+				return nil, false
+			}
+			step := strings.Trim(path[1:end], `'"`)
+			path = path[end+1:]
+
+			if index, err := strconv.Atoi(step); err == nil {
+				list, ok := current.([]any)
+				if !ok || index < 0 || index >= len(list) {
+					return nil, false
+				}
+				current = list[index]
+				continue
+			}
+
+			object, ok := current.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if current, ok = object[step]; !ok {
+				return nil, false
+			}
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end == -1 {
+				end = len(path)
+			}
+			key := path[:end]
+			path = path[end:]
+
+			object, ok := current.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if current, ok = object[key]; !ok {
+				return nil, false
+			}
+		}
+	}
+
+	return current, true
+}
+
+// lookupString resolves a path and renders scalar values as a string
+func lookupString(data any, path string) string {
+	if path == "" {
+		return ""
+	}
+	value, ok := lookupPath(data, path)
+	if !ok {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// renderTemplate replaces {{name}} placeholders with the given values.
+// escape is applied to every value, use url.QueryEscape for URLs.
+func renderTemplate(tmpl string, vars map[string]string, escape func(string) string) string {
+	for name, value := range vars {
+		if escape != nil {
+			value = escape(value)
+		}
+		tmpl = strings.ReplaceAll(tmpl, "{{"+name+"}}", value)
+	}
+	return tmpl
+}
+
+// Template vars holding a full URL, inserted into URL templates as they are
+var urlTemplateVars = map[string]bool{"link": true}
+
+// renderURLTemplate renders a detail URL template, path escaping the values of segments like
+// {{id}} and inserting full URLs like {{link}} as they are
+func renderURLTemplate(tmpl string, vars map[string]string) string {
+	escaped := make(map[string]string, len(vars))
+	for name, value := range vars {
+		if !urlTemplateVars[name] {
+			value = url.PathEscape(value)
+		}
+		escaped[name] = value
+	}
+	return renderTemplate(tmpl, escaped, nil)
+}
+
+// parseDate parses a job date according to the spec's date_format
+func parseDate(value string, dateFormat string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	switch dateFormat {
+	case "unix", "unix_ms":
+		number, err := strconv.ParseInt(strings.Split(value, ".")[0], 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s timestamp %q: %w", dateFormat, value, err)
+		}
+		if dateFormat == "unix_ms" {
+			return time.UnixMilli(number), nil
+		}
+		return time.Unix(number, 0), nil
+	case "", "rfc3339":
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t, nil
+		}
+		if dateFormat == "" {
+			if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	default:
+		t, err := time.ParseInLocation(dateFormat, value, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q for format %q: %w", value, dateFormat, err)
+		}
+		return t, nil
+	}
+}
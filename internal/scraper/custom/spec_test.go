@@ -0,0 +1,215 @@
+package custom
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		expectError string
+		checkFn     func(*Spec) bool
+	}{
+		{
+			name: "Minimal spec gets defaults",
+			raw:  `{"list_url":"https://api.example.com/jobs","jobs_path":"$.jobs","fields":{"title":"title","link":"url","date":"posted"}}`,
+			checkFn: func(spec *Spec) bool {
+				return spec.Method == "GET" &&
+					spec.Pagination.Style == PaginationNone &&
+					spec.Pagination.PageSize == defaultPageSize &&
+					spec.Pagination.MaxPages == defaultMaxPages &&
+					spec.DescriptionFormat == "html"
+			},
+		},
+		{
+			name: "Detail method defaults to GET",
+			raw:  `{"list_url":"https://api.example.com/jobs","method":"post","jobs_path":"$","fields":{"title":"title","date":"posted"},"link_template":"https://example.com/jobs/{{id}}","detail":{"url_template":"https://api.example.com/jobs/{{id}}","description_path":"$.description"}}`,
+			checkFn: func(spec *Spec) bool {
+				return spec.Method == "POST" && spec.Detail.Method == "GET"
+			},
+		},
+		{
+			name:        "Unknown field",
+			raw:         `{"list_url":"https://api.example.com/jobs","jobs_path":"$","fields":{"title":"t","link":"l","date":"d"},"unknown":true}`,
+			expectError: "unknown field",
+		},
+		{
+			name:        "Missing list URL",
+			raw:         `{"jobs_path":"$","fields":{"title":"t","link":"l","date":"d"}}`,
+			expectError: "list_url is required",
+		},
+		{
+			name:        "Relative list URL",
+			raw:         `{"list_url":"/jobs","jobs_path":"$","fields":{"title":"t","link":"l","date":"d"}}`,
+			expectError: "list_url must be an absolute URL",
+		},
+		{
+			name:        "Unsupported method",
+			raw:         `{"list_url":"https://api.example.com/jobs","method":"PUT","jobs_path":"$","fields":{"title":"t","link":"l","date":"d"}}`,
+			expectError: "method must be GET or POST",
+		},
+		{
+			name:        "Unsupported pagination",
+			raw:         `{"list_url":"https://api.example.com/jobs","pagination":{"style":"scroll"},"jobs_path":"$","fields":{"title":"t","link":"l","date":"d"}}`,
+			expectError: "pagination.style",
+		},
+		{
+			name:        "Cursor pagination without cursor path",
+			raw:         `{"list_url":"https://api.example.com/jobs","pagination":{"style":"cursor"},"jobs_path":"$","fields":{"title":"t","link":"l","date":"d"}}`,
+			expectError: "cursor_path is required",
+		},
+		{
+			name:        "Missing link",
+			raw:         `{"list_url":"https://api.example.com/jobs","jobs_path":"$","fields":{"title":"t","date":"d"}}`,
+			expectError: "fields.link or link_template is required",
+		},
+		{
+			name:        "Detail without description path",
+			raw:         `{"list_url":"https://api.example.com/jobs","jobs_path":"$","fields":{"title":"t","link":"l","date":"d"},"detail":{"url_template":"https://api.example.com/jobs/{{id}}"}}`,
+			expectError: "detail.description_path is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpec([]byte(tt.raw))
+
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.checkFn != nil && !tt.checkFn(spec) {
+				t.Errorf("Spec check failed: %+v", spec)
+			}
+		})
+	}
+}
+
+func TestCompactSpec(t *testing.T) {
+	raw := `{
+		"list_url": "https://api.example.com/jobs",
+		"jobs_path": "$.jobs",
+		"fields": {"title": "title", "link": "url", "date": "posted"}
+	}`
+
+	_, compacted, err := CompactSpec([]byte(raw))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"list_url":"https://api.example.com/jobs","jobs_path":"$.jobs","fields":{"title":"title","link":"url","date":"posted"}}`
+	if compacted != expected {
+		t.Errorf("Expected %s, got %s", expected, compacted)
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	data, err := decodeJSON([]byte(`{"data":{"jobs":[{"id":1234567890123,"title":"Engineer","meta":{"remote":true}}],"next":"abc"},"top-level":"x"}`))
+	if err != nil {
+		t.Fatalf("Failed to decode test JSON: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "Nested with dollar", path: "$.data.next", expected: "abc"},
+		{name: "Nested without dollar", path: "data.next", expected: "abc"},
+		{name: "Array index", path: "$.data.jobs[0].title", expected: "Engineer"},
+		{name: "Large number stays intact", path: "$.data.jobs[0].id", expected: "1234567890123"},
+		{name: "Boolean", path: "$.data.jobs[0].meta.remote", expected: "true"},
+		{name: "Bracket key", path: "$['top-level']", expected: "x"},
+		{name: "Index out of range", path: "$.data.jobs[5].title", expected: ""},
+		{name: "Missing key", path: "$.data.missing", expected: ""},
+		{name: "Non scalar value", path: "$.data.jobs", expected: ""},
+		{name: "Empty path", path: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := lookupString(data, tt.path)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+
+	if jobs, ok := lookupPath(data, "$.data.jobs"); !ok {
+		t.Errorf("Expected jobs list to resolve")
+	} else if list, isList := jobs.([]any); !isList || len(list) != 1 {
+		t.Errorf("Expected a list with one job, got %v", jobs)
+	}
+	if root, ok := lookupPath(data, "$"); !ok || root == nil {
+		t.Errorf("Expected $ to resolve to the root")
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	vars := map[string]string{"offset": "20", "cursor": "a b&c"}
+
+	if result := renderTemplate(`{"offset":{{offset}},"cursor":"{{cursor}}"}`, vars, nil); result != `{"offset":20,"cursor":"a b&c"}` {
+		t.Errorf("Unexpected body render %q", result)
+	}
+	if result := renderTemplate("https://api.example.com/jobs?after={{cursor}}", vars, url.QueryEscape); result != "https://api.example.com/jobs?after=a+b%26c" {
+		t.Errorf("Unexpected URL render %q", result)
+	}
+	if result := renderTemplate("no placeholders", vars, nil); result != "no placeholders" {
+		t.Errorf("Unexpected render %q", result)
+	}
+
+	detailVars := map[string]string{"id": "12/3 4", "link": "https://careers.example.com/jobs/123?ref=api"}
+	if result := renderURLTemplate("{{link}}", detailVars); result != "https://careers.example.com/jobs/123?ref=api" {
+		t.Errorf("Unexpected link render %q", result)
+	}
+	if result := renderURLTemplate("https://api.example.com/jobs/{{id}}", detailVars); result != "https://api.example.com/jobs/12%2F3%204" {
+		t.Errorf("Unexpected id render %q", result)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		dateFormat  string
+		expected    time.Time
+		expectError bool
+	}{
+		{name: "Default RFC3339", value: "2025-11-06T14:30:00Z", expected: time.Date(2025, 11, 6, 14, 30, 0, 0, time.UTC)},
+		{name: "Default date only", value: "2025-11-06", expected: time.Date(2025, 11, 6, 0, 0, 0, 0, time.Local)},
+		{name: "Explicit rfc3339 rejects date only", value: "2025-11-06", dateFormat: "rfc3339", expectError: true},
+		{name: "Unix seconds", value: "1730900000", dateFormat: "unix", expected: time.Unix(1730900000, 0)},
+		{name: "Unix milliseconds", value: "1730900000000", dateFormat: "unix_ms", expected: time.UnixMilli(1730900000000)},
+		{name: "Go layout", value: "06/11/2025", dateFormat: "02/01/2006", expected: time.Date(2025, 11, 6, 0, 0, 0, 0, time.Local)},
+		{name: "Invalid unix", value: "yesterday", dateFormat: "unix", expectError: true},
+		{name: "Layout mismatch", value: "2025-11-06", dateFormat: "02/01/2006", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseDate(tt.value, tt.dateFormat)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -1,12 +1,17 @@
 package greenhouse
 
 import (
+	"context"
 	"fmt"
+	"html"
 	"job-scraper/internal/db"
 	"job-scraper/internal/scraper/common"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/k3a/html2text"
@@ -17,16 +22,32 @@ type GreenhouseJobLocation struct {
 	Name string `json:"name"`
 }
 
+type GreenhouseDepartment struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type GreenhouseOffice struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+// GreenhouseJobListItem is one job of the /jobs listing. Content, Departments and Offices
+// are only filled when the listing is requested with content=true
 type GreenhouseJobListItem struct {
-	ID             int                   `json:"id"`
-	Title          string                `json:"title"`
-	AbsoluteURL    string                `json:"absolute_url"`
-	Location       GreenhouseJobLocation `json:"location"`
-	UpdatedAt      string                `json:"updated_at"`
-	RequisitionID  string                `json:"requisition_id"`
-	InternalJobID  int                   `json:"internal_job_id"`
-	FirstPublished string                `json:"first_published"`
-	Language       string                `json:"language"`
+	ID             int                    `json:"id"`
+	Title          string                 `json:"title"`
+	AbsoluteURL    string                 `json:"absolute_url"`
+	Location       GreenhouseJobLocation  `json:"location"`
+	UpdatedAt      string                 `json:"updated_at"`
+	RequisitionID  string                 `json:"requisition_id"`
+	InternalJobID  int                    `json:"internal_job_id"`
+	FirstPublished string                 `json:"first_published"`
+	Language       string                 `json:"language"`
+	Content        string                 `json:"content"`
+	Departments    []GreenhouseDepartment `json:"departments"`
+	Offices        []GreenhouseOffice     `json:"offices"`
 }
 
 type GreenhouseJobListResponse struct {
@@ -47,6 +68,110 @@ type GreenhouseJobDetail struct {
 
 type GreenhouseScraper struct{}
 
+const (
+	greenhouseAPIBaseURL   = "https://boards-api.greenhouse.io"
+	greenhouseEUAPIBaseURL = "https://boards-api.eu.greenhouse.io"
+)
+
+var greenhouseBoardTokenRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// TransformBoardURLToAPIURL normalises any form of Greenhouse board URL to the boards API URL
+// Accepted forms:
+//   - https://boards.greenhouse.io/{board_token}
+//   - https://job-boards.greenhouse.io/{board_token}/jobs/{job_id}
+//   - https://job-boards.eu.greenhouse.io/{board_token}
+//   - https://boards.greenhouse.io/embed/job_board?for={board_token}
+//   - https://boards-api.greenhouse.io/v1/boards/{board_token}/jobs
+//   - a bare board token
+//
+// Example: https://job-boards.greenhouse.io/sonyinteractiveentertainmentglobal
+// -> https://boards-api.greenhouse.io/v1/boards/sonyinteractiveentertainmentglobal
+// EU hosted boards are served by boards-api.eu.greenhouse.io
+func TransformBoardURLToAPIURL(boardURL string) (string, error) {
+	boardURL = strings.TrimSpace(boardURL)
+	if greenhouseBoardTokenRegex.MatchString(boardURL) {
+		return greenhouseAPIBaseURL + "/v1/boards/" + boardURL, nil
+	}
+
+	parsedURL, err := url.Parse(boardURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	pathParts := common.SplitURLPath(parsedURL.Path)
+
+	apiBaseURL := greenhouseAPIBaseURL
+	var boardToken string
+	host := strings.ToLower(parsedURL.Host)
+	switch host {
+	case "boards.greenhouse.io", "job-boards.greenhouse.io", "boards.eu.greenhouse.io", "job-boards.eu.greenhouse.io":
+		if strings.Contains(host, ".eu.") {
+			apiBaseURL = greenhouseEUAPIBaseURL
+		}
+		if len(pathParts) > 0 && pathParts[0] == "embed" {
+			// Format: https://boards.greenhouse.io/embed/job_board?for={board_token}
+			boardToken = parsedURL.Query().Get("for")
+		} else if len(pathParts) > 0 {
+			// Format: https://boards.greenhouse.io/{board_token}/jobs/{job_id}
+			boardToken = pathParts[0]
+		}
+	case "boards-api.greenhouse.io", "boards-api.eu.greenhouse.io":
+		// Format: https://boards-api.greenhouse.io/v1/boards/{board_token}/jobs
+		apiBaseURL = "https://" + host
+		if len(pathParts) >= 3 && pathParts[1] == "boards" {
+			boardToken = pathParts[2]
+		}
+	default:
+		return "", fmt.Errorf("not a Greenhouse board URL: %s", parsedURL.Host)
+	}
+
+	if !greenhouseBoardTokenRegex.MatchString(boardToken) {
+		return "", fmt.Errorf("could not extract board token from URL")
+	}
+
+	return apiBaseURL + "/v1/boards/" + boardToken, nil
+}
+
+// ProbeBoard checks that a boards API URL points to an existing board.
+// Greenhouse answers 404 for unknown board tokens.
+func ProbeBoard(apiURL string) error {
+	rClient := resty.New()
+	rClient.SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	rClient.SetTimeout(15 * time.Second)
+	defer rClient.Close()
+
+	resp, err := rClient.R().
+		SetHeader("Accept", "application/json").
+		Get(apiURL)
+	if err != nil {
+		return fmt.Errorf("failed to reach Greenhouse: %w", err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("board not found at %s, check the board token", apiURL)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("greenhouse returned status %d for %s", resp.StatusCode(), apiURL)
+	}
+
+	return nil
+}
+
+// NormaliseBoardURL translates a Greenhouse board URL to the boards API URL and checks the
+// board token exists, so typos are caught before the company is saved
+func NormaliseBoardURL(boardURL string) (string, error) {
+	apiURL, err := TransformBoardURLToAPIURL(boardURL)
+	if err != nil {
+		return "", fmt.Errorf("could not translate board URL: %w", err)
+	}
+
+	if err := ProbeBoard(apiURL); err != nil {
+		return "", fmt.Errorf("board validation failed: %w", err)
+	}
+
+	return apiURL, nil
+}
+
 func parseGreenhouseDate(dateStr string) (time.Time, error) {
 	// Parse ISO 8601 format: "2025-10-29T09:22:45-04:00"
 	parsedTime, err := time.Parse(time.RFC3339, dateStr)
@@ -67,23 +192,61 @@ func isJobPublishedRecently(firstPublished string, scrapeDateLimitTruncated time
 	return common.ShouldScrapeJob(publishedTime, scrapeDateLimitTruncated)
 }
 
-func listJobsAndStartDetailsScrape(company db.Companies, scrapeDateLimitTruncated time.Time, jobDetailScrapeChannel chan<- *db.Jobs) {
+// buildGreenhouseDescription converts the job content to plain text and appends the
+// location, departments and offices returned by the content=true listing.
+// Greenhouse returns content HTML escaped, so it's unescaped before conversion
+func buildGreenhouseDescription(jobItem GreenhouseJobListItem) string {
+	jobDetails := common.RemoveExtraNewlines(common.CleanUTF8String(html2text.HTML2Text(html.UnescapeString(jobItem.Content))))
+
+	if jobItem.Location.Name != "" {
+		jobDetails += "\n\nLocation: " + jobItem.Location.Name
+	}
+
+	departments := make([]string, 0, len(jobItem.Departments))
+	for _, department := range jobItem.Departments {
+		departments = append(departments, department.Name)
+	}
+	if len(departments) > 0 {
+		jobDetails += "\n\nDepartments: " + strings.Join(departments, ", ")
+	}
+
+	offices := make([]string, 0, len(jobItem.Offices))
+	for _, office := range jobItem.Offices {
+		offices = append(offices, office.Name)
+	}
+	if len(offices) > 0 {
+		jobDetails += "\n\nOffices: " + strings.Join(offices, ", ")
+	}
+
+	return jobDetails
+}
+
+// jobDetailRequest is what a queued job needs to fetch its details, the board is the company's
+type jobDetailRequest struct {
+	JobID string `json:"job_id"`
+}
+
+// Shared by every job details request, resty clients are safe for concurrent use
+var detailClient = resty.New().SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+
+func listJobs(ctx context.Context, company db.Companies, scrapeDateLimitTruncated time.Time, sink common.ScrapeSink) (int, error) {
 	rClient := resty.New()
 	rClient.SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
 	defer rClient.Close()
 
-	// BaseUrl should be in format: https://boards-api.greenhouse.io/{board_token}
+	// BaseUrl should be in format: https://boards-api.greenhouse.io/v1/boards/{board_token}
 	if company.BaseUrl == "" {
-		slog.Error("Base URL not found for company", "company", company.Name)
-		return
+		return 0, fmt.Errorf("base URL not found for company")
 	}
 
 	apiURL := company.BaseUrl + "/jobs"
-	slog.Info(apiURL)
 
 	var jobListResp GreenhouseJobListResponse
 
+	// content=true returns the description, departments and offices of every job in the
+	// listing, so a board costs one request instead of one per job
 	resp, err := rClient.R().
+		SetContext(ctx).
 		SetHeaders(map[string]string{
 			"Accept":         "application/json",
 			"cache-control":  "no-cache",
@@ -91,103 +254,130 @@ func listJobsAndStartDetailsScrape(company db.Companies, scrapeDateLimitTruncate
 			"sec-fetch-mode": "cors",
 			"sec-fetch-site": "cross-site",
 		}).
+		SetQueryParam("content", "true").
 		SetResult(&jobListResp).
 		Get(apiURL)
 
 	if err != nil {
-		slog.Error("Failed to fetch jobs", "company", company.Name, "error", err)
-		return
+		return 0, fmt.Errorf("failed to fetch jobs: %w", err)
+	}
+
+	if resp.IsError() {
+		return 0, fmt.Errorf("greenhouse returned status %d", resp.StatusCode())
 	}
 
 	result := resp.Result().(*GreenhouseJobListResponse)
+	sink.PageFetched(company, 1, len(result.Jobs))
 
-	slog.Info("Successfully fetched jobs", "company", company.Name, "total_jobs", len(result.Jobs))
+	slog.Info("[Greenhouse_Scraper] Successfully fetched jobs", "company", company.Name, "total_jobs", len(result.Jobs))
 
 	if len(result.Jobs) == 0 {
-		slog.Info("No jobs found", "company", company.Name)
-		return
+		slog.Info("[Greenhouse_Scraper] No jobs found", "company", company.Name)
+		return 0, nil
 	}
 
 	recentJobsCount := 0
+	detailJobsCount := 0
 	for _, jobItem := range result.Jobs {
-		// Check if job was published within the last 24 hours
-		if isJobPublishedRecently(jobItem.FirstPublished, scrapeDateLimitTruncated) {
-			recentJobsCount++
-
-			publishedTime, _ := parseGreenhouseDate(jobItem.FirstPublished)
-
-			job := &db.Jobs{
-				JobHash:      "",
-				JobId:        jobItem.RequisitionID,
-				JobRole:      jobItem.Title,
-				JobDetails:   "",
-				JobPostDate:  publishedTime.Format("2006-01-02"),
-				JobLink:      jobItem.AbsoluteURL,
-				JobAISummary: "",
-				CompanyName:  company.Name,
+		// Check if job was published within the scrape window
+		if !isJobPublishedRecently(jobItem.FirstPublished, scrapeDateLimitTruncated) {
+			continue
+		}
+		recentJobsCount++
+
+		publishedTime, _ := parseGreenhouseDate(jobItem.FirstPublished)
+
+		job := &db.Jobs{
+			JobHash:      common.GetSHA256Hash(jobItem.AbsoluteURL),
+			JobId:        jobItem.RequisitionID,
+			JobRole:      jobItem.Title,
+			JobDetails:   "",
+			JobPostDate:  publishedTime.Format("2006-01-02"),
+			JobLink:      jobItem.AbsoluteURL,
+			JobAISummary: "",
+			CompanyName:  company.Name,
+		}
+
+		// Jobs missing content in the listing fall back to the per job detail request
+		if strings.TrimSpace(jobItem.Content) == "" {
+			jobID := strconv.Itoa(jobItem.ID)
+			if jobItem.ID == 0 {
+				jobID = extractJobIDFromURL(jobItem.AbsoluteURL)
 			}
 
-			jobDetailScrapeChannel <- job
+			detailJobsCount++
+			sink.JobQueued(company, job, jobDetailRequest{JobID: jobID})
+			continue
 		}
+
+		job.JobDetails = buildGreenhouseDescription(jobItem)
+		sink.JobScraped(job)
 	}
 
-	slog.Info("Recent jobs queued for detailed scraping",
+	slog.Info("[Greenhouse_Scraper] Recent jobs scraped",
 		"company", company.Name,
 		"recent_jobs", recentJobsCount,
+		"queued_for_details", detailJobsCount,
 		"total_jobs", len(result.Jobs))
+
+	return recentJobsCount, nil
 }
 
-func (gs GreenhouseScraper) jobDetailsScraperWorker(baseURL string, jobChannel <-chan *db.Jobs) {
-	slog.Debug("[Greenhouse_Scraper] Worker started to scrape Job Details")
-	rClient := resty.New()
-	rClient.SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
-	defer rClient.Close()
+func (gs GreenhouseScraper) fetchJobDetails(ctx context.Context, rClient *resty.Client, baseURL, jobID string) (*GreenhouseJobDetail, error) {
+	if jobID == "" {
+		return nil, fmt.Errorf("missing job ID")
+	}
 
-	for job := range jobChannel {
-		// Extract job ID from the job object
-		// We need to get the internal job ID from the absolute URL or use the ID from the list
-		// For now, we'll extract it from the URL
-		jobID := extractJobIDFromURL(job.JobLink)
-		if jobID == "" {
-			slog.Error("[Greenhouse_Scraper_Worker] Failed to extract job ID from URL", "jobLink", job.JobLink)
-			continue
-		}
+	apiURL := fmt.Sprintf("%s/jobs/%s", baseURL, jobID)
 
-		apiURL := fmt.Sprintf("%s/jobs/%s", baseURL, jobID)
-
-		var jobDetailsResp GreenhouseJobDetail
-		resp, err := rClient.R().
-			SetHeaders(map[string]string{
-				"Accept":         "application/json",
-				"cache-control":  "no-cache",
-				"sec-fetch-dest": "empty",
-				"sec-fetch-mode": "cors",
-				"sec-fetch-site": "cross-site",
-			}).
-			SetResult(&jobDetailsResp).
-			Get(apiURL)
-
-		if err != nil {
-			slog.Error("[Greenhouse_Scraper_Worker] Failed to fetch job details", "jobLink", job.JobLink, "error", err)
-			continue
-		}
+	var jobDetailsResp GreenhouseJobDetail
+	resp, err := rClient.R().
+		SetContext(ctx).
+		SetHeaders(map[string]string{
+			"Accept":         "application/json",
+			"cache-control":  "no-cache",
+			"sec-fetch-dest": "empty",
+			"sec-fetch-mode": "cors",
+			"sec-fetch-site": "cross-site",
+		}).
+		SetResult(&jobDetailsResp).
+		Get(apiURL)
 
-		// Get the parsed result
-		result := resp.Result().(*GreenhouseJobDetail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch job details: %w", err)
+	}
 
-		// Update job details
-		job.JobId = result.RequisitionID
-		job.JobLink = result.AbsoluteURL
-		job.JobDetails = common.RemoveExtraNewlines(common.CleanUTF8String(html2text.HTML2Text(result.Content)))
-		job.JobRole = result.Title
-		job.JobHash = common.GetSHA256Hash(job.JobLink)
+	if resp.IsError() {
+		return nil, fmt.Errorf("greenhouse returned status %d for %s", resp.StatusCode(), apiURL)
+	}
+
+	return resp.Result().(*GreenhouseJobDetail), nil
+}
 
-		// Insert job into database
-		common.InsertJobToDB(job, "Greenhouse_Scraper")
+func (gs GreenhouseScraper) ScrapeJob(ctx context.Context, queued common.QueuedJob, scrapeDayLimit time.Time, sink common.ScrapeSink) error {
+	var req jobDetailRequest
+	if err := queued.DecodeDetail(&req); err != nil {
+		return err
+	}
 
-		slog.Info("[Greenhouse_Scraper_Worker] Job Scraped", "JobLink", job.JobLink)
+	result, err := gs.fetchJobDetails(ctx, detailClient, queued.Company.BaseUrl, req.JobID)
+	if err != nil {
+		return err
 	}
-	slog.Info("[Greenhouse_Scraper_Worker] Job Details Worker shutting down")
+
+	// Update job details
+	job := queued.Job
+	job.JobId = result.RequisitionID
+	job.JobLink = result.AbsoluteURL
+	job.JobDetails = common.RemoveExtraNewlines(common.CleanUTF8String(html2text.HTML2Text(html.UnescapeString(result.Content))))
+	job.JobRole = result.Title
+	job.JobHash = common.GetSHA256Hash(job.JobLink)
+	job.CompanyName = queued.Company.Name
+
+	sink.JobScraped(job)
+
+	slog.Info("[Greenhouse_Scraper_Worker] Job Scraped", "JobLink", job.JobLink)
+	return nil
 }
 
 // extractJobIDFromURL extracts the job ID from Greenhouse job URL
@@ -200,38 +390,7 @@ func extractJobIDFromURL(url string) string {
 	return ""
 }
 
-func (gs GreenhouseScraper) StartScraping(companiesToScrape <-chan db.Companies, scrapeDayLimit time.Time) {
+func (gs GreenhouseScraper) ListJobs(ctx context.Context, company db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) (int, error) {
 	// Get date at midnight using centralized function
-	scrapeDateLimitTruncated := common.GetDateMidnight(scrapeDayLimit)
-
-	jobDetailScrapeChannel := make(chan *db.Jobs, 10000)
-	slog.Info("[Greenhouse_Scraper] Greenhouse Jobs Details channel created")
-
-	scraperWorkerCount := 4
-
-	// Use WaitGroup to track company listing workers
-	var wg sync.WaitGroup
-
-	for company := range companiesToScrape {
-		// Start workers for this company's base URL
-		if company.BaseUrl == "" {
-			slog.Error("[Greenhouse_Scraper] Base URL not found for company", "company", company.Name)
-			continue
-		}
-
-		// Start workers if not already started
-		for range make([]struct{}, scraperWorkerCount) {
-			go gs.jobDetailsScraperWorker(company.BaseUrl, jobDetailScrapeChannel)
-			slog.Info("[Greenhouse_Scraper] Job details scraper worker started", "company", company.Name)
-		}
-
-		wg.Go(func() {
-			listJobsAndStartDetailsScrape(company, scrapeDateLimitTruncated, jobDetailScrapeChannel)
-		})
-	}
-
-	// Wait for all companies to finish listing jobs, then close the channel
-	wg.Wait()
-	close(jobDetailScrapeChannel)
-	slog.Info("[Greenhouse_Scraper] Greenhouse Companies Job list complete.")
+	return listJobs(ctx, company, common.GetDateMidnight(scrapeDayLimit), sink)
 }
@@ -1,6 +1,7 @@
 package greenhouse
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -189,3 +190,118 @@ func TestExtractJobIDFromURL(t *testing.T) {
 		})
 	}
 }
+
+func TestTransformBoardURLToAPIURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		boardURL    string
+		expectedURL string
+		expectError bool
+	}{
+		{
+			name:        "boards.greenhouse.io URL",
+			boardURL:    "https://boards.greenhouse.io/sonyinteractiveentertainmentglobal",
+			expectedURL: "https://boards-api.greenhouse.io/v1/boards/sonyinteractiveentertainmentglobal",
+		},
+		{
+			name:        "job-boards.greenhouse.io job URL",
+			boardURL:    "https://job-boards.greenhouse.io/sonyinteractiveentertainmentglobal/jobs/5686915004",
+			expectedURL: "https://boards-api.greenhouse.io/v1/boards/sonyinteractiveentertainmentglobal",
+		},
+		{
+			name:        "Already an API URL",
+			boardURL:    "https://boards-api.greenhouse.io/v1/boards/sonyinteractiveentertainmentglobal/jobs",
+			expectedURL: "https://boards-api.greenhouse.io/v1/boards/sonyinteractiveentertainmentglobal",
+		},
+		{
+			name:        "EU job board URL",
+			boardURL:    "https://job-boards.eu.greenhouse.io/examplecompany",
+			expectedURL: "https://boards-api.eu.greenhouse.io/v1/boards/examplecompany",
+		},
+		{
+			name:        "Embed job board URL",
+			boardURL:    "https://boards.greenhouse.io/embed/job_board?for=examplecompany",
+			expectedURL: "https://boards-api.greenhouse.io/v1/boards/examplecompany",
+		},
+		{
+			name:        "Embed job app URL",
+			boardURL:    "https://job-boards.greenhouse.io/embed/job_app?for=examplecompany&token=5686915004",
+			expectedURL: "https://boards-api.greenhouse.io/v1/boards/examplecompany",
+		},
+		{
+			name:        "EU API URL",
+			boardURL:    "https://boards-api.eu.greenhouse.io/v1/boards/examplecompany/jobs?content=true",
+			expectedURL: "https://boards-api.eu.greenhouse.io/v1/boards/examplecompany",
+		},
+		{
+			name:        "Bare board token",
+			boardURL:    " examplecompany ",
+			expectedURL: "https://boards-api.greenhouse.io/v1/boards/examplecompany",
+		},
+		{
+			name:        "Embed URL without for parameter",
+			boardURL:    "https://boards.greenhouse.io/embed/job_board",
+			expectError: true,
+		},
+		{
+			name:        "Missing board token",
+			boardURL:    "https://boards.greenhouse.io/",
+			expectError: true,
+		},
+		{
+			name:        "Not a Greenhouse URL",
+			boardURL:    "https://jobs.lever.co/netflix",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TransformBoardURLToAPIURL(tt.boardURL)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if result != tt.expectedURL {
+				t.Errorf("Expected %q, got %q", tt.expectedURL, result)
+			}
+		})
+	}
+}
+
+func TestBuildGreenhouseDescription(t *testing.T) {
+	jobItem := GreenhouseJobListItem{
+		Content:     "&lt;p&gt;We are hiring a &lt;strong&gt;Backend Engineer&lt;/strong&gt;.&lt;/p&gt;",
+		Location:    GreenhouseJobLocation{Name: "Remote - US"},
+		Departments: []GreenhouseDepartment{{ID: 1, Name: "Engineering"}, {ID: 2, Name: "Platform"}},
+		Offices:     []GreenhouseOffice{{ID: 3, Name: "San Francisco", Location: "San Francisco, CA"}},
+	}
+
+	result := buildGreenhouseDescription(jobItem)
+
+	for _, expected := range []string{
+		"We are hiring a Backend Engineer.",
+		"Location: Remote - US",
+		"Departments: Engineering, Platform",
+		"Offices: San Francisco",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected description to contain %q, got %q", expected, result)
+		}
+	}
+	if strings.Contains(result, "<") || strings.Contains(result, "&lt;") {
+		t.Errorf("Expected HTML to be unescaped and stripped, got %q", result)
+	}
+
+	// Jobs without departments or offices don't get empty lines appended
+	result = buildGreenhouseDescription(GreenhouseJobListItem{Content: "&lt;p&gt;Hello&lt;/p&gt;"})
+	if result != "Hello" {
+		t.Errorf("Expected %q, got %q", "Hello", result)
+	}
+}
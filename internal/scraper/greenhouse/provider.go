@@ -0,0 +1,44 @@
+package greenhouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/scraper/common"
+	"job-scraper/internal/types"
+)
+
+func init() {
+	scraper.Register(scraper.Provider{
+		Name:            types.Greenhouse,
+		New:             func() scraper.Scraper { return GreenhouseScraper{} },
+		ValidatePayload: validatePayload,
+		NormaliseURL:    normaliseURL,
+		MatchesURL: common.HostIs("boards.greenhouse.io", "job-boards.greenhouse.io", "boards-api.greenhouse.io",
+			"boards.eu.greenhouse.io", "job-boards.eu.greenhouse.io", "boards-api.eu.greenhouse.io"),
+		URLPatterns: "host is boards.greenhouse.io, job-boards.greenhouse.io or boards-api.greenhouse.io (including .eu. hosts)",
+	})
+}
+
+func normaliseURL(boardURL string) (scraper.CompanySource, error) {
+	apiURL, err := NormaliseBoardURL(boardURL)
+	if err != nil {
+		return scraper.CompanySource{}, err
+	}
+	return scraper.CompanySource{BaseUrl: apiURL}, nil
+}
+
+func validatePayload(payload []byte) (scraper.CompanySource, error) {
+	var greenhouseCompData api_models.AddGreenhouseCompanyScrapeList
+	if err := json.Unmarshal(payload, &greenhouseCompData); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	// Accept any board form (board page, job page, embed, EU board, API URL or bare token)
+	source, err := normaliseURL(greenhouseCompData.BaseUrl)
+	if err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid base_url: %w", err)
+	}
+	return source, nil
+}
@@ -1,27 +1,100 @@
 package scraper
 
 import (
+	"context"
 	"job-scraper/internal/db"
-	"job-scraper/internal/scraper/greenhouse"
-	"job-scraper/internal/scraper/oraclecloud"
-	"job-scraper/internal/scraper/workday"
+	"job-scraper/internal/scraper/common"
 	"job-scraper/internal/types"
+	"log/slog"
+	"sync"
 	"time"
 )
 
-type scraper interface {
-	StartScraping(companiesToScrape <-chan db.Companies, scrapeDayLimit time.Time)
+// Scraper scrapes the jobs of a provider's companies in two stages: listing a company's jobs,
+// then fetching the details of each listed job that needs them. Jobs and per-company outcomes
+// are reported to the sink, persistence and scheduling of the stages are up to the caller.
+type Scraper interface {
+	// ListJobs lists the jobs of a company posted on or after scrapeDayLimit, the zero time
+	// lists every job, and returns the number found. Jobs complete once listed go to
+	// sink.JobScraped, the others to sink.JobQueued for ScrapeJob.
+	ListJobs(ctx context.Context, company db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) (int, error)
+	// ScrapeJob fetches the details of a job queued by ListJobs and hands the result to
+	// sink.JobScraped. An error leaves the job to the caller, which may retry it.
+	ScrapeJob(ctx context.Context, queued common.QueuedJob, scrapeDayLimit time.Time, sink common.ScrapeSink) error
 }
 
-func JobScraperFactory(provider types.ScrapableWebsites) scraper {
-	switch provider {
-	case types.Workday:
-		return workday.WorkdayScraper{}
-	case types.Greenhouse:
-		return greenhouse.GreenhouseScraper{}
-	case types.OracleCloud:
-		return oraclecloud.OracleCloudScraper{}
-	default:
+// Detail workers run per provider
+const DetailWorkers = 4
+
+// Jobs waiting for a detail worker in memory before listing blocks
+const detailQueueSize = 10000
+
+// JobScraperFactory returns the scraper of a registered provider, nil if there is none
+func JobScraperFactory(provider types.ScrapableWebsites) Scraper {
+	registered, ok := Lookup(provider)
+	if !ok {
 		return nil
 	}
+	return registered.New()
+}
+
+// queueSink hands the jobs queued by listing to the in-memory detail workers
+type queueSink struct {
+	common.ScrapeSink
+	queue chan<- common.QueuedJob
+}
+
+func (s queueSink) JobQueued(company db.Companies, job *db.Jobs, detail any) {
+	queued, err := common.NewQueuedJob(company, job, detail)
+	if err != nil {
+		s.ScrapeSink.JobFailed(job, err)
+		return
+	}
+	// Reported before queueing, the worker owns the job once it's sent
+	s.ScrapeSink.JobQueued(company, job, detail)
+	s.queue <- queued
+}
+
+// Scrape lists every company received until the channel is closed at once and fetches the
+// queued jobs' details with DetailWorkers workers, all in memory. It returns once every queued
+// job is handled; after ctx is cancelled, the remaining jobs are reported as failed. Nothing
+// survives the process, scrape runs go through the task queue instead.
+func Scrape(ctx context.Context, jobScraper Scraper, companiesToScrape <-chan db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) {
+	queue := make(chan common.QueuedJob, detailQueueSize)
+	listingSink := queueSink{ScrapeSink: sink, queue: queue}
+
+	var workerWg sync.WaitGroup
+	for range DetailWorkers {
+		workerWg.Go(func() {
+			for queued := range queue {
+				// Keep draining after cancellation so listers never block on a full queue
+				if err := ctx.Err(); err != nil {
+					sink.JobFailed(queued.Job, err)
+					continue
+				}
+				if err := jobScraper.ScrapeJob(ctx, queued, scrapeDayLimit, sink); err != nil {
+					slog.Error("Failed to scrape job details", "company", queued.Company.Name, "jobLink", queued.Job.JobLink, "error", err)
+					sink.JobFailed(queued.Job, err)
+				}
+			}
+		})
+	}
+
+	var wg sync.WaitGroup
+	for company := range companiesToScrape {
+		wg.Go(func() {
+			sink.CompanyStarted(company)
+			jobsFound, err := jobScraper.ListJobs(ctx, company, scrapeDayLimit, listingSink)
+			if err != nil {
+				slog.Error("Failed to list jobs", "company", company.Name, "career_site_type", company.CareerSiteType, "error", err)
+			}
+			sink.CompanyListed(company, jobsFound, err)
+		})
+	}
+
+	// Wait for all companies to finish listing jobs, then close the queue and wait for the
+	// workers to drain it
+	wg.Wait()
+	close(queue)
+	workerWg.Wait()
 }
@@ -0,0 +1,435 @@
+package jsonld
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper/common"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/k3a/html2text"
+	"resty.dev/v3"
+)
+
+// JobPosting is the subset of the schema.org JobPosting type (Google for Jobs markup) we store.
+// Identifier, JobLocation and BaseSalary come in several shapes, so they are kept raw and
+// formatted by the helpers below.
+type JobPosting struct {
+	Title       string          `json:"title"`
+	DatePosted  string          `json:"datePosted"`
+	Description string          `json:"description"`
+	URL         string          `json:"url"`
+	Identifier  json.RawMessage `json:"identifier"`
+	JobLocation json.RawMessage `json:"jobLocation"`
+	BaseSalary  json.RawMessage `json:"baseSalary"`
+}
+
+type JsonLDScraper struct{}
+
+// Shared by every job page request, resty clients are safe for concurrent use
+var detailClient = resty.New().SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+
+// Hard cap on job pages followed per company so a careers page linking to the
+// whole site can't turn into a crawl
+const maxJobLinksPerCompany = 500
+
+var (
+	ldJSONScriptRegex = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']application/ld\+json["'][^>]*>(.*?)</script>`)
+	hrefRegex         = regexp.MustCompile(`(?i)<a\s[^>]*href\s*=\s*["']([^"']+)["']`)
+	jobLinkPathRegex  = regexp.MustCompile(`(?i)/(jobs?|careers?|positions?|openings?|vacanc(y|ies)|requisitions?|roles?)/[^/?#]+`)
+)
+
+// ValidateCareersURL checks that the careers listing URL is an absolute http(s) URL
+func ValidateCareersURL(careersURL string) (string, error) {
+	careersURL = strings.TrimSpace(careersURL)
+	parsedURL, err := url.Parse(careersURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return "", fmt.Errorf("careers URL must be an absolute http(s) URL")
+	}
+	return parsedURL.String(), nil
+}
+
+// extractJobLinks returns the de-duplicated job page links on the listing page.
+// Only links on the listing page's host whose path looks like a job page are kept.
+func extractJobLinks(listingURL *url.URL, pageHTML string) []string {
+	seen := map[string]bool{listingURL.String(): true}
+	var links []string
+
+	for _, match := range hrefRegex.FindAllStringSubmatch(pageHTML, -1) {
+		linkURL, err := listingURL.Parse(html.UnescapeString(strings.TrimSpace(match[1])))
+		if err != nil {
+			continue
+		}
+		if linkURL.Host != listingURL.Host || !jobLinkPathRegex.MatchString(linkURL.Path) {
+			continue
+		}
+		linkURL.Fragment = ""
+		link := linkURL.String()
+		if seen[link] {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+	}
+
+	return links
+}
+
+// extractJobPostings returns every JobPosting found in the page's ld+json blocks.
+// Blocks can hold a single object, an array, or an @graph of objects.
+func extractJobPostings(pageHTML string) []JobPosting {
+	var postings []JobPosting
+
+	for _, match := range ldJSONScriptRegex.FindAllStringSubmatch(pageHTML, -1) {
+		var block any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(match[1])), &block); err != nil {
+			slog.Debug("[JsonLD_Scraper] Skipping invalid ld+json block", "error", err)
+			continue
+		}
+		postings = append(postings, collectJobPostings(block)...)
+	}
+
+	return postings
+}
+
+func collectJobPostings(node any) []JobPosting {
+	var postings []JobPosting
+
+	switch value := node.(type) {
+	case []any:
+		for _, item := range value {
+			postings = append(postings, collectJobPostings(item)...)
+		}
+	case map[string]any:
+		if isJobPostingType(value["@type"]) {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return postings
+			}
+			var posting JobPosting
+			if err := json.Unmarshal(raw, &posting); err == nil {
+				postings = append(postings, posting)
+			}
+			return postings
+		}
+		for _, key := range []string{"@graph", "itemListElement", "item"} {
+			if child, ok := value[key]; ok {
+				postings = append(postings, collectJobPostings(child)...)
+			}
+		}
+	}
+
+	return postings
+}
+
+func isJobPostingType(typeValue any) bool {
+	switch value := typeValue.(type) {
+	case string:
+		return value == "JobPosting"
+	case []any:
+		for _, item := range value {
+			if str, ok := item.(string); ok && str == "JobPosting" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseDatePosted(dateStr string) (time.Time, error) {
+	// datePosted is ISO 8601, either a date or a date time
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02",
+	}
+
+	dateStr = strings.TrimSpace(dateStr)
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, dateStr, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported datePosted format: %q", dateStr)
+}
+
+// formatIdentifier returns the identifier value. It is either plain text or a PropertyValue.
+func formatIdentifier(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case map[string]any:
+		return scalarToString(v["value"])
+	}
+	return ""
+}
+
+// formatJobLocation joins the addresses of the job's Place(s) into one line per place
+func formatJobLocation(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+
+	places, ok := value.([]any)
+	if !ok {
+		places = []any{value}
+	}
+
+	var locations []string
+	for _, place := range places {
+		switch p := place.(type) {
+		case string:
+			locations = append(locations, p)
+		case map[string]any:
+			address, ok := p["address"].(map[string]any)
+			if !ok {
+				if name := scalarToString(p["name"]); name != "" {
+					locations = append(locations, name)
+				}
+				continue
+			}
+			var parts []string
+			for _, key := range []string{"addressLocality", "addressRegion", "addressCountry"} {
+				part := scalarToString(address[key])
+				if country, ok := address[key].(map[string]any); ok {
+					part = scalarToString(country["name"])
+				}
+				if part != "" {
+					parts = append(parts, part)
+				}
+			}
+			if len(parts) > 0 {
+				locations = append(locations, strings.Join(parts, ", "))
+			}
+		}
+	}
+
+	return strings.Join(locations, "; ")
+}
+
+// formatBaseSalary renders a MonetaryAmount, ex: "USD 120000 - 150000 per YEAR"
+func formatBaseSalary(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var salary struct {
+		Currency string `json:"currency"`
+		Value    any    `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &salary); err != nil {
+		return ""
+	}
+
+	amount := ""
+	unit := ""
+	switch v := salary.Value.(type) {
+	case map[string]any:
+		minValue := scalarToString(v["minValue"])
+		maxValue := scalarToString(v["maxValue"])
+		switch {
+		case minValue != "" && maxValue != "":
+			amount = minValue + " - " + maxValue
+		case scalarToString(v["value"]) != "":
+			amount = scalarToString(v["value"])
+		default:
+			amount = minValue + maxValue
+		}
+		unit = scalarToString(v["unitText"])
+	default:
+		amount = scalarToString(v)
+	}
+
+	if amount == "" {
+		return ""
+	}
+
+	result := strings.TrimSpace(salary.Currency + " " + amount)
+	if unit != "" {
+		result += " per " + unit
+	}
+	return result
+}
+
+func scalarToString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+	}
+	return ""
+}
+
+// resolvePostingURL resolves the url of a posting, often relative, against the page it was found
+// on. The page itself is the link when the posting has no usable url.
+func resolvePostingURL(pageURL string, postingURL string) string {
+	postingURL = strings.TrimSpace(postingURL)
+	if postingURL == "" {
+		return pageURL
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return pageURL
+	}
+	resolved, err := base.Parse(postingURL)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") || resolved.Host == "" {
+		return pageURL
+	}
+	return resolved.String()
+}
+
+// buildJobFromPosting maps a JobPosting into a db.Jobs row.
+// Location and salary have no column of their own so they are appended to the details.
+func buildJobFromPosting(posting JobPosting, pageURL string, jobPostDate time.Time, companyName string) *db.Jobs {
+	description := posting.Description
+	// Some sites HTML-escape the markup inside the JSON string
+	if strings.Contains(description, "&lt;") {
+		description = html.UnescapeString(description)
+	}
+	jobDetails := common.RemoveExtraNewlines(common.CleanUTF8String(html2text.HTML2Text(description)))
+
+	if location := formatJobLocation(posting.JobLocation); location != "" {
+		jobDetails += "\n\nLocation: " + location
+	}
+	if salary := formatBaseSalary(posting.BaseSalary); salary != "" {
+		jobDetails += "\n\nSalary: " + salary
+	}
+
+	jobLink := resolvePostingURL(pageURL, posting.URL)
+
+	jobId := formatIdentifier(posting.Identifier)
+	if jobId == "" {
+		jobId = jobLink
+	}
+
+	return &db.Jobs{
+		JobHash:      common.GetSHA256Hash(jobLink),
+		JobId:        jobId,
+		JobRole:      common.CleanUTF8String(html.UnescapeString(posting.Title)),
+		JobDetails:   jobDetails,
+		JobPostDate:  jobPostDate.Format("2006-01-02"),
+		JobLink:      jobLink,
+		JobAISummary: "",
+		CompanyName:  companyName,
+	}
+}
+
+// listJobs queues every job page linked from the careers page, up to maxJobLinksPerCompany, and
+// returns the number of jobs queued. Dates are only known once a page is fetched, so pages with
+// postings outside the scrape window are counted too.
+func listJobs(ctx context.Context, company db.Companies, sink common.ScrapeSink) (int, error) {
+	rClient := resty.New()
+	rClient.SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	defer rClient.Close()
+
+	// BaseUrl is the careers listing page, ex: https://careers.example.com/jobs
+	listingURL, err := url.Parse(company.BaseUrl)
+	if err != nil || listingURL.Host == "" {
+		return 0, fmt.Errorf("invalid careers URL: %s", company.BaseUrl)
+	}
+
+	resp, err := rClient.R().
+		SetContext(ctx).
+		SetHeader("Accept", "text/html").
+		Get(company.BaseUrl)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch careers page: %w", err)
+	}
+
+	if resp.IsError() {
+		return 0, fmt.Errorf("careers page returned status %d", resp.StatusCode())
+	}
+
+	jobLinks := extractJobLinks(listingURL, resp.String())
+	sink.PageFetched(company, 1, len(jobLinks))
+	if len(jobLinks) > maxJobLinksPerCompany {
+		slog.Warn("[JsonLD_Scraper] Too many job links on careers page, truncating",
+			"company", company.Name,
+			"found", len(jobLinks),
+			"limit", maxJobLinksPerCompany)
+		sink.Warning(company, fmt.Sprintf("found %d job links on careers page, only the first %d are scraped", len(jobLinks), maxJobLinksPerCompany))
+		jobLinks = jobLinks[:maxJobLinksPerCompany]
+	}
+
+	slog.Info("[JsonLD_Scraper] Job links found on careers page", "company", company.Name, "job_links", len(jobLinks))
+
+	// Listing pages don't carry posting dates, so every job page is fetched and
+	// the date cutoff is applied by the worker
+	jobsQueued := 0
+	for _, link := range jobLinks {
+		sink.JobQueued(company, &db.Jobs{JobLink: link, CompanyName: company.Name}, nil)
+		jobsQueued++
+	}
+
+	return jobsQueued, nil
+}
+
+func (js JsonLDScraper) ListJobs(ctx context.Context, company db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) (int, error) {
+	return listJobs(ctx, company, sink)
+}
+
+// ScrapeJob fetches a queued job page and scrapes every posting on it within the scrape window
+func (js JsonLDScraper) ScrapeJob(ctx context.Context, queued common.QueuedJob, scrapeDayLimit time.Time, sink common.ScrapeSink) error {
+	// Get date at midnight using centralized function
+	scrapeDateLimitTruncated := common.GetDateMidnight(scrapeDayLimit)
+	pageURL := queued.Job.JobLink
+
+	resp, err := detailClient.R().
+		SetContext(ctx).
+		SetHeader("Accept", "text/html").
+		Get(pageURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch job page: %w", err)
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("job page returned status %d", resp.StatusCode())
+	}
+
+	for _, posting := range extractJobPostings(resp.String()) {
+		jobPostDate, err := parseDatePosted(posting.DatePosted)
+		if err != nil {
+			slog.Error("[JsonLD_Scraper_Worker] Failed to parse datePosted", "pageURL", pageURL, "error", err)
+			sink.Warning(queued.Company, fmt.Sprintf("skipped job on %s: failed to parse datePosted %q", pageURL, posting.DatePosted))
+			continue
+		}
+
+		// Check if we should scrape this job using centralized function
+		if !common.ShouldScrapeJob(jobPostDate, scrapeDateLimitTruncated) {
+			continue
+		}
+
+		job := buildJobFromPosting(posting, pageURL, jobPostDate, queued.Company.Name)
+
+		sink.JobScraped(job)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Note: GetSHA256Hash, CleanUTF8String, and RemoveExtraNewlines tests
+// are in job-scraper/internal/scraper/common/utils_test.go
+// This test file only contains JSON-LD-specific function tests.
+
+func TestValidateCareersURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		careersURL  string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:       "Valid https URL",
+			careersURL: " https://careers.example.com/jobs ",
+			expected:   "https://careers.example.com/jobs",
+		},
+		{
+			name:        "Missing scheme",
+			careersURL:  "careers.example.com/jobs",
+			expectError: true,
+		},
+		{
+			name:        "Unsupported scheme",
+			careersURL:  "ftp://careers.example.com/jobs",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateCareersURL(tt.careersURL)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestExtractJobLinks(t *testing.T) {
+	listingURL, _ := url.Parse("https://careers.example.com/jobs")
+	pageHTML := `
+		<a href="/jobs/123-software-engineer">Software Engineer</a>
+		<a class="btn" href="https://careers.example.com/jobs/456-data-engineer#apply">Data Engineer</a>
+		<a href="/jobs/123-software-engineer">Duplicate</a>
+		<a href="https://other.example.com/jobs/789">Other host</a>
+		<a href="/about">About</a>
+		<a href="/jobs">Listing itself</a>
+		<a href="/careers/position/42?ref=list&amp;src=site">Query link</a>
+	`
+
+	expected := []string{
+		"https://careers.example.com/jobs/123-software-engineer",
+		"https://careers.example.com/jobs/456-data-engineer",
+		"https://careers.example.com/careers/position/42?ref=list&src=site",
+	}
+
+	result := extractJobLinks(listingURL, pageHTML)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestExtractJobPostings(t *testing.T) {
+	tests := []struct {
+		name           string
+		pageHTML       string
+		expectedTitles []string
+	}{
+		{
+			name:           "Single object",
+			pageHTML:       `<script type="application/ld+json">{"@context":"https://schema.org","@type":"JobPosting","title":"Engineer"}</script>`,
+			expectedTitles: []string{"Engineer"},
+		},
+		{
+			name:           "Array of objects",
+			pageHTML:       `<script type='application/ld+json'>[{"@type":"Organization","name":"Example"},{"@type":"JobPosting","title":"Designer"}]</script>`,
+			expectedTitles: []string{"Designer"},
+		},
+		{
+			name:           "Graph with multiple types",
+			pageHTML:       `<script type="application/ld+json">{"@graph":[{"@type":"WebPage"},{"@type":["JobPosting"],"title":"Analyst"}]}</script>`,
+			expectedTitles: []string{"Analyst"},
+		},
+		{
+			name: "Multiple blocks with invalid JSON ignored",
+			pageHTML: `<script type="application/ld+json">{not json}</script>
+				<script type="text/javascript">{"@type":"JobPosting","title":"Ignored"}</script>
+				<script type="application/ld+json">{"@type":"JobPosting","title":"Manager"}</script>`,
+			expectedTitles: []string{"Manager"},
+		},
+		{
+			name:           "No JobPosting",
+			pageHTML:       `<script type="application/ld+json">{"@type":"Organization","name":"Example"}</script>`,
+			expectedTitles: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var titles []string
+			for _, posting := range extractJobPostings(tt.pageHTML) {
+				titles = append(titles, posting.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.expectedTitles) {
+				t.Errorf("Expected %v, got %v", tt.expectedTitles, titles)
+			}
+		})
+	}
+}
+
+func TestParseDatePosted(t *testing.T) {
+	tests := []struct {
+		name        string
+		dateStr     string
+		expectError bool
+		expected    time.Time
+	}{
+		{
+			name:     "Date only",
+			dateStr:  "2025-11-06",
+			expected: time.Date(2025, 11, 6, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "Date time with offset",
+			dateStr:  "2025-11-06T09:30:00-05:00",
+			expected: time.Date(2025, 11, 6, 14, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Date time without offset",
+			dateStr:  "2025-11-06T09:30:00",
+			expected: time.Date(2025, 11, 6, 9, 30, 0, 0, time.Local),
+		},
+		{
+			name:        "Invalid date",
+			dateStr:     "November 6, 2025",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseDatePosted(tt.dateStr)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{name: "PropertyValue", raw: `{"@type":"PropertyValue","name":"Example","value":"REQ-123"}`, expected: "REQ-123"},
+		{name: "PropertyValue with number", raw: `{"@type":"PropertyValue","value":456}`, expected: "456"},
+		{name: "Plain string", raw: `"REQ-789"`, expected: "REQ-789"},
+		{name: "Missing", raw: ``, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatIdentifier(json.RawMessage(tt.raw))
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatJobLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{
+			name:     "Single place",
+			raw:      `{"@type":"Place","address":{"@type":"PostalAddress","addressLocality":"Austin","addressRegion":"TX","addressCountry":"US"}}`,
+			expected: "Austin, TX, US",
+		},
+		{
+			name:     "Multiple places with country object",
+			raw:      `[{"address":{"addressLocality":"Berlin","addressCountry":{"@type":"Country","name":"Germany"}}},{"address":{"addressLocality":"Paris"}}]`,
+			expected: "Berlin, Germany; Paris",
+		},
+		{
+			name:     "Missing",
+			raw:      ``,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatJobLocation(json.RawMessage(tt.raw))
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatBaseSalary(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{
+			name:     "Range",
+			raw:      `{"@type":"MonetaryAmount","currency":"USD","value":{"@type":"QuantitativeValue","minValue":120000,"maxValue":150000,"unitText":"YEAR"}}`,
+			expected: "USD 120000 - 150000 per YEAR",
+		},
+		{
+			name:     "Single value",
+			raw:      `{"currency":"EUR","value":{"value":45.5,"unitText":"HOUR"}}`,
+			expected: "EUR 45.5 per HOUR",
+		},
+		{
+			name:     "Plain number",
+			raw:      `{"currency":"GBP","value":60000}`,
+			expected: "GBP 60000",
+		},
+		{
+			name:     "Missing",
+			raw:      ``,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatBaseSalary(json.RawMessage(tt.raw))
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildJobFromPosting(t *testing.T) {
+	posting := JobPosting{
+		Title:       "Backend Engineer &amp; SRE",
+		Description: "&lt;p&gt;Run the platform.&lt;/p&gt;",
+		Identifier:  json.RawMessage(`{"value":"REQ-1"}`),
+		JobLocation: json.RawMessage(`{"address":{"addressLocality":"Austin","addressRegion":"TX"}}`),
+		BaseSalary:  json.RawMessage(`{"currency":"USD","value":{"minValue":100000,"maxValue":120000,"unitText":"YEAR"}}`),
+	}
+	postDate := time.Date(2025, 11, 6, 0, 0, 0, 0, time.Local)
+
+	job := buildJobFromPosting(posting, "https://careers.example.com/jobs/1", postDate, "Example")
+
+	if job.JobRole != "Backend Engineer & SRE" {
+		t.Errorf("Unexpected job role %q", job.JobRole)
+	}
+	if job.JobId != "REQ-1" {
+		t.Errorf("Unexpected job id %q", job.JobId)
+	}
+	if job.JobLink != "https://careers.example.com/jobs/1" {
+		t.Errorf("Unexpected job link %q", job.JobLink)
+	}
+	if job.JobPostDate != "2025-11-06" {
+		t.Errorf("Unexpected job post date %q", job.JobPostDate)
+	}
+	for _, expected := range []string{"Run the platform.", "Location: Austin, TX", "Salary: USD 100000 - 120000 per YEAR"} {
+		if !strings.Contains(job.JobDetails, expected) {
+			t.Errorf("Expected job details to contain %q, got %q", expected, job.JobDetails)
+		}
+	}
+	if strings.Contains(job.JobDetails, "<p>") {
+		t.Errorf("Expected HTML to be stripped, got %q", job.JobDetails)
+	}
+}
+
+func TestResolvePostingURL(t *testing.T) {
+	pageURL := "https://careers.example.com/jobs/1"
+
+	tests := []struct {
+		name       string
+		postingURL string
+		expected   string
+	}{
+		{
+			name:       "No url",
+			postingURL: "",
+			expected:   pageURL,
+		},
+		{
+			name:       "Absolute url",
+			postingURL: "https://jobs.example.com/postings/1",
+			expected:   "https://jobs.example.com/postings/1",
+		},
+		{
+			name:       "Root relative url",
+			postingURL: "/careers/backend-engineer",
+			expected:   "https://careers.example.com/careers/backend-engineer",
+		},
+		{
+			name:       "Path relative url",
+			postingURL: "1?ref=ld",
+			expected:   "https://careers.example.com/jobs/1?ref=ld",
+		},
+		{
+			name:       "Not a web url",
+			postingURL: "mailto:jobs@example.com",
+			expected:   pageURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := resolvePostingURL(pageURL, tt.postingURL)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/types"
+)
+
+// Any site can carry JSON-LD, so the provider doesn't take part in auto-detection
+func init() {
+	scraper.Register(scraper.Provider{
+		Name:            types.JsonLD,
+		New:             func() scraper.Scraper { return JsonLDScraper{} },
+		ValidatePayload: validatePayload,
+		NormaliseURL:    normaliseURL,
+	})
+}
+
+func normaliseURL(careersURL string) (scraper.CompanySource, error) {
+	validURL, err := ValidateCareersURL(careersURL)
+	if err != nil {
+		return scraper.CompanySource{}, err
+	}
+	return scraper.CompanySource{BaseUrl: validURL}, nil
+}
+
+func validatePayload(payload []byte) (scraper.CompanySource, error) {
+	var jsonldCompData api_models.AddJsonLDCompanyScrapeList
+	if err := json.Unmarshal(payload, &jsonldCompData); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	source, err := normaliseURL(jsonldCompData.CareersUrl)
+	if err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid careers_url: %w", err)
+	}
+	return source, nil
+}
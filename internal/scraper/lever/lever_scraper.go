@@ -0,0 +1,201 @@
+package lever
+
+import (
+	"context"
+	"fmt"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper/common"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/k3a/html2text"
+	"resty.dev/v3"
+)
+
+type LeverPostingCategories struct {
+	Commitment string `json:"commitment"`
+	Department string `json:"department"`
+	Location   string `json:"location"`
+	Team       string `json:"team"`
+}
+
+type LeverPostingList struct {
+	Text    string `json:"text"`
+	Content string `json:"content"`
+}
+
+type LeverPosting struct {
+	ID          string                 `json:"id"`
+	Text        string                 `json:"text"`
+	HostedURL   string                 `json:"hostedUrl"`
+	ApplyURL    string                 `json:"applyUrl"`
+	CreatedAt   int64                  `json:"createdAt"`
+	Categories  LeverPostingCategories `json:"categories"`
+	Description string                 `json:"description"`
+	Lists       []LeverPostingList     `json:"lists"`
+	Additional  string                 `json:"additional"`
+}
+
+type LeverScraper struct{}
+
+// Lever returns every posting in one call unless skip/limit are given, page through
+// to avoid giant responses for large boards
+const leverPageSize = 100
+
+// BuildPostingsURL returns the Lever postings API URL for a site
+// Example: netflix -> https://api.lever.co/v0/postings/netflix
+func BuildPostingsURL(site string) string {
+	return "https://api.lever.co/v0/postings/" + strings.Trim(strings.TrimSpace(site), "/")
+}
+
+// TransformBoardURLToAPIURL converts a Lever job board URL to the postings API URL
+// Example: https://jobs.lever.co/netflix -> https://api.lever.co/v0/postings/netflix
+// EU hosted boards (jobs.eu.lever.co) are served by api.eu.lever.co
+func TransformBoardURLToAPIURL(boardURL string) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(boardURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	pathParts := common.SplitURLPath(parsedURL.Path)
+
+	var apiHost, site string
+	switch strings.ToLower(parsedURL.Host) {
+	case "jobs.lever.co", "jobs.eu.lever.co":
+		// Format: https://jobs.lever.co/{site}/{posting_id}
+		apiHost = strings.Replace(strings.ToLower(parsedURL.Host), "jobs.", "api.", 1)
+		if len(pathParts) > 0 {
+			site = pathParts[0]
+		}
+	case "api.lever.co", "api.eu.lever.co":
+		// Format: https://api.lever.co/v0/postings/{site}
+		apiHost = strings.ToLower(parsedURL.Host)
+		if len(pathParts) >= 3 && pathParts[1] == "postings" {
+			site = pathParts[2]
+		}
+	default:
+		return "", fmt.Errorf("not a Lever job board URL: %s", parsedURL.Host)
+	}
+
+	if site == "" {
+		return "", fmt.Errorf("could not extract site from URL path")
+	}
+
+	return fmt.Sprintf("https://%s/v0/postings/%s", apiHost, site), nil
+}
+
+// parseLeverDate converts Lever's createdAt (milliseconds since epoch) to time
+func parseLeverDate(createdAt int64) time.Time {
+	return time.UnixMilli(createdAt)
+}
+
+// buildLeverDescription joins the description, the titled lists (Responsibilities,
+// Requirements, ...) and the closing section into one plain text description
+func buildLeverDescription(posting LeverPosting) string {
+	var sb strings.Builder
+	sb.WriteString(posting.Description)
+	for _, list := range posting.Lists {
+		sb.WriteString("<h3>" + list.Text + "</h3>")
+		sb.WriteString("<ul>" + list.Content + "</ul>")
+	}
+	sb.WriteString(posting.Additional)
+
+	return common.RemoveExtraNewlines(common.CleanUTF8String(html2text.HTML2Text(sb.String())))
+}
+
+func listAndScrapeJobs(ctx context.Context, company db.Companies, scrapeDateLimitTruncated time.Time, sink common.ScrapeSink) (int, error) {
+	rClient := resty.New()
+	rClient.SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	defer rClient.Close()
+
+	// BaseUrl should be in format: https://api.lever.co/v0/postings/{site}
+	if company.BaseUrl == "" {
+		return 0, fmt.Errorf("base URL not found for company")
+	}
+
+	skip := 0
+	page := 0
+	totalJobs := 0
+	recentJobsCount := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return recentJobsCount, err
+		}
+
+		var postings []LeverPosting
+
+		resp, err := rClient.R().
+			SetContext(ctx).
+			SetHeaders(map[string]string{
+				"Accept":        "application/json",
+				"cache-control": "no-cache",
+			}).
+			SetQueryParams(map[string]string{
+				"mode":  "json",
+				"skip":  fmt.Sprintf("%d", skip),
+				"limit": fmt.Sprintf("%d", leverPageSize),
+			}).
+			SetResult(&postings).
+			Get(company.BaseUrl)
+
+		if err != nil {
+			return recentJobsCount, fmt.Errorf("failed to fetch jobs at skip %d: %w", skip, err)
+		}
+
+		if resp.IsError() {
+			return recentJobsCount, fmt.Errorf("lever returned status %d at skip %d", resp.StatusCode(), skip)
+		}
+
+		result := *resp.Result().(*[]LeverPosting)
+		totalJobs += len(result)
+		page++
+		sink.PageFetched(company, page, len(result))
+
+		// Lever doesn't sort postings by date, so every page has to be checked
+		for _, posting := range result {
+			jobPostDate := parseLeverDate(posting.CreatedAt)
+			if !common.ShouldScrapeJob(jobPostDate, scrapeDateLimitTruncated) {
+				continue
+			}
+			recentJobsCount++
+
+			job := &db.Jobs{
+				JobHash:      common.GetSHA256Hash(posting.HostedURL),
+				JobId:        posting.ID,
+				JobRole:      posting.Text,
+				JobDetails:   buildLeverDescription(posting),
+				JobPostDate:  jobPostDate.Format("2006-01-02"),
+				JobLink:      posting.HostedURL,
+				JobAISummary: "",
+				CompanyName:  company.Name,
+			}
+
+			// Lever returns the full description in the listing, no detail request needed
+			sink.JobScraped(job)
+		}
+
+		if len(result) < leverPageSize {
+			break
+		}
+		skip += len(result)
+	}
+
+	slog.Info("[Lever_Scraper] Recent jobs scraped",
+		"company", company.Name,
+		"recent_jobs", recentJobsCount,
+		"total_jobs", totalJobs)
+
+	return recentJobsCount, nil
+}
+
+func (ls LeverScraper) ListJobs(ctx context.Context, company db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) (int, error) {
+	// Get date at midnight using centralized function
+	return listAndScrapeJobs(ctx, company, common.GetDateMidnight(scrapeDayLimit), sink)
+}
+
+// ScrapeJob is never called, the listing carries every job's details so no job is queued
+func (ls LeverScraper) ScrapeJob(ctx context.Context, queued common.QueuedJob, scrapeDayLimit time.Time, sink common.ScrapeSink) error {
+	return fmt.Errorf("lever jobs have no details request")
+}
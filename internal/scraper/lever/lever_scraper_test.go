@@ -0,0 +1,155 @@
+package lever
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Note: GetSHA256Hash, CleanUTF8String, and RemoveExtraNewlines tests
+// are in job-scraper/internal/scraper/common/utils_test.go
+// This test file only contains Lever-specific function tests.
+
+func TestBuildPostingsURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		site     string
+		expected string
+	}{
+		{
+			name:     "Plain site name",
+			site:     "netflix",
+			expected: "https://api.lever.co/v0/postings/netflix",
+		},
+		{
+			name:     "Site name with surrounding whitespace",
+			site:     "  netflix ",
+			expected: "https://api.lever.co/v0/postings/netflix",
+		},
+		{
+			name:     "Site name with slashes",
+			site:     "/netflix/",
+			expected: "https://api.lever.co/v0/postings/netflix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BuildPostingsURL(tt.site)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseLeverDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		createdAt int64
+		expected  time.Time
+	}{
+		{
+			name:      "Milliseconds since epoch",
+			createdAt: 1730900000000,
+			expected:  time.Date(2024, 11, 6, 13, 33, 20, 0, time.UTC),
+		},
+		{
+			name:      "Zero value",
+			createdAt: 0,
+			expected:  time.Unix(0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseLeverDate(tt.createdAt)
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildLeverDescription(t *testing.T) {
+	posting := LeverPosting{
+		Description: "<div>We are hiring an engineer.</div>",
+		Lists: []LeverPostingList{
+			{Text: "Requirements", Content: "<li>Go</li><li>Postgres</li>"},
+		},
+		Additional: "<div>Benefits included.</div>",
+	}
+
+	result := buildLeverDescription(posting)
+
+	for _, expected := range []string{"We are hiring an engineer.", "Requirements", "Go", "Postgres", "Benefits included."} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected description to contain %q, got %q", expected, result)
+		}
+	}
+	if strings.Contains(result, "<") {
+		t.Errorf("Expected HTML to be stripped, got %q", result)
+	}
+	if strings.Contains(result, "\n\n\n") {
+		t.Errorf("Expected extra newlines to be removed, got %q", result)
+	}
+}
+
+func TestTransformBoardURLToAPIURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		boardURL    string
+		expectedURL string
+		expectError bool
+	}{
+		{
+			name:        "Job board URL",
+			boardURL:    "https://jobs.lever.co/netflix",
+			expectedURL: "https://api.lever.co/v0/postings/netflix",
+		},
+		{
+			name:        "Posting URL",
+			boardURL:    "https://jobs.lever.co/netflix/0c6a2b5e-1234-4c1f-9a3e-abcdef123456",
+			expectedURL: "https://api.lever.co/v0/postings/netflix",
+		},
+		{
+			name:        "EU job board URL",
+			boardURL:    "https://jobs.eu.lever.co/example",
+			expectedURL: "https://api.eu.lever.co/v0/postings/example",
+		},
+		{
+			name:        "Already an API URL",
+			boardURL:    "https://api.lever.co/v0/postings/netflix?mode=json",
+			expectedURL: "https://api.lever.co/v0/postings/netflix",
+		},
+		{
+			name:        "Missing site",
+			boardURL:    "https://jobs.lever.co/",
+			expectError: true,
+		},
+		{
+			name:        "Not a Lever URL",
+			boardURL:    "https://boards.greenhouse.io/example",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TransformBoardURLToAPIURL(tt.boardURL)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if result != tt.expectedURL {
+				t.Errorf("Expected %q, got %q", tt.expectedURL, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package lever
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/scraper/common"
+	"job-scraper/internal/types"
+)
+
+func init() {
+	scraper.Register(scraper.Provider{
+		Name:            types.Lever,
+		New:             func() scraper.Scraper { return LeverScraper{} },
+		ValidatePayload: validatePayload,
+		NormaliseURL:    normaliseURL,
+		MatchesURL:      common.HostIs("jobs.lever.co", "jobs.eu.lever.co", "api.lever.co", "api.eu.lever.co"),
+		URLPatterns:     "host is jobs.lever.co, jobs.eu.lever.co or api.lever.co",
+	})
+}
+
+func normaliseURL(boardURL string) (scraper.CompanySource, error) {
+	apiURL, err := TransformBoardURLToAPIURL(boardURL)
+	if err != nil {
+		return scraper.CompanySource{}, err
+	}
+	return scraper.CompanySource{BaseUrl: apiURL}, nil
+}
+
+func validatePayload(payload []byte) (scraper.CompanySource, error) {
+	var leverCompData api_models.AddLeverCompanyScrapeList
+	if err := json.Unmarshal(payload, &leverCompData); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	if leverCompData.Site == "" {
+		return scraper.CompanySource{}, fmt.Errorf("site is required")
+	}
+	return scraper.CompanySource{BaseUrl: BuildPostingsURL(leverCompData.Site)}, nil
+}
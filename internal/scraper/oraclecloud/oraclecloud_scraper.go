@@ -1,6 +1,7 @@
 package oraclecloud
 
 import (
+	"context"
 	"fmt"
 	"job-scraper/internal/db"
 	"job-scraper/internal/scraper/common"
@@ -8,7 +9,6 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/k3a/html2text"
@@ -250,19 +250,16 @@ func buildJobDetailURL(baseURL, siteNumber, jobId string) string {
 	)
 }
 
-type jobDetailRequest struct {
-	requisition *OracleCloudRequisitionItem
-	company     db.Companies
-	baseURL     string
-	siteNumber  string
-}
+// Shared by every job details request, resty clients are safe for concurrent use
+var detailClient = resty.New().SetHeader("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 
-func (ocs OracleCloudScraper) fetchJobDetails(rClient *resty.Client, baseURL, siteNumber, jobId string) (*db.Jobs, error) {
+func (ocs OracleCloudScraper) fetchJobDetails(ctx context.Context, rClient *resty.Client, baseURL, siteNumber, jobId string) (*db.Jobs, error) {
 	detailURL := buildJobDetailURL(baseURL, siteNumber, jobId)
 
 	var detailsResp OracleCloudJobDetailsResponse
 
 	resp, err := rClient.R().
+		SetContext(ctx).
 		SetHeaders(map[string]string{
 			"Accept":        "application/json",
 			"Cache-Control": "no-cache",
@@ -319,43 +316,35 @@ func (ocs OracleCloudScraper) fetchJobDetails(rClient *resty.Client, baseURL, si
 	return job, nil
 }
 
-func (ocs OracleCloudScraper) jobDetailsScraperWorker(jobChannel <-chan *jobDetailRequest) {
-	slog.Debug("[OracleCloud_Scraper_Worker] Worker started")
-	rClient := resty.New()
-	rClient.SetHeader("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-	defer rClient.Close()
+// ScrapeJob fetches a queued requisition by its ID, the base URL and site number are the company's
+func (ocs OracleCloudScraper) ScrapeJob(ctx context.Context, queued common.QueuedJob, scrapeDayLimit time.Time, sink common.ScrapeSink) error {
+	baseURL, siteNumber, err := ParseOracleAPIURL(queued.Company.BaseUrl)
+	if err != nil {
+		return fmt.Errorf("failed to parse company URL: %w", err)
+	}
 
-	for req := range jobChannel {
-		job, err := ocs.fetchJobDetails(rClient, req.baseURL, req.siteNumber, req.requisition.Id)
-		if err != nil {
-			slog.Error("[OracleCloud_Scraper_Worker] Failed to fetch job details",
-				"jobId", req.requisition.Id,
-				"company", req.company.Name,
-				"error", err)
-			continue
-		}
+	job, err := ocs.fetchJobDetails(ctx, detailClient, baseURL, siteNumber, queued.Job.JobId)
+	if err != nil {
+		return err
+	}
 
-		// Set company name
-		job.CompanyName = req.company.Name
+	// Set company name
+	job.CompanyName = queued.Company.Name
 
-		// Insert job into database
-		common.InsertJobToDB(job, "OracleCloud_Scraper")
-	}
-	slog.Info("[OracleCloud_Scraper_Worker] Worker shutting down")
+	sink.JobScraped(job)
+	return nil
 }
 
-func listJobsAndStartDetailsScrape(
+func listJobs(
+	ctx context.Context,
 	company db.Companies,
 	scrapeDateLimitTruncated time.Time,
-	jobDetailScrapeChannel chan<- *jobDetailRequest,
-) {
-	// Parse company base URL to extract base URL and site number
-	baseURL, siteNumber, err := ParseOracleAPIURL(company.BaseUrl)
+	sink common.ScrapeSink,
+) (int, error) {
+	// Parse company base URL to extract base URL
+	baseURL, _, err := ParseOracleAPIURL(company.BaseUrl)
 	if err != nil {
-		slog.Error("[OracleCloud_Scraper] Failed to parse company URL",
-			"company", company.Name,
-			"error", err)
-		return
+		return 0, fmt.Errorf("failed to parse company URL: %w", err)
 	}
 
 	rClient := resty.New()
@@ -365,8 +354,7 @@ func listJobsAndStartDetailsScrape(
 	// Parse the stored API URL to get finder parameters
 	parsedURL, err := url.Parse(company.BaseUrl)
 	if err != nil {
-		slog.Error("Failed to parse Oracle API URL", "company", company.Name, "error", err)
-		return
+		return 0, fmt.Errorf("failed to parse Oracle API URL: %w", err)
 	}
 
 	// Extract finder parameter
@@ -384,8 +372,7 @@ func listJobsAndStartDetailsScrape(
 	}
 
 	if finder == "" {
-		slog.Error("Could not extract finder from stored URL", "company", company.Name)
-		return
+		return 0, fmt.Errorf("could not extract finder from stored URL")
 	}
 
 	// Parse finder to extract parameters
@@ -409,10 +396,16 @@ func listJobsAndStartDetailsScrape(
 		}
 	}
 
+	jobsFound := 0
 	offset := 0
 	limit := 25
+	page := 0
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return jobsFound, err
+		}
+
 		// Update offset in finder
 		finderParams["offset"] = fmt.Sprintf("%d", offset)
 		finderParams["limit"] = fmt.Sprintf("%d", limit)
@@ -465,6 +458,7 @@ func listJobsAndStartDetailsScrape(
 		var oracleResp OracleCloudJobListResponse
 
 		resp, err := rClient.R().
+			SetContext(ctx).
 			SetHeaders(map[string]string{
 				"Accept":        "application/json",
 				"Cache-Control": "no-cache",
@@ -473,8 +467,11 @@ func listJobsAndStartDetailsScrape(
 			Get(apiURL)
 
 		if err != nil {
-			slog.Error("Failed to fetch Oracle Cloud jobs", "company", company.Name, "error", err)
-			break
+			return jobsFound, fmt.Errorf("failed to fetch Oracle Cloud jobs at offset %d: %w", offset, err)
+		}
+
+		if resp.IsError() {
+			return jobsFound, fmt.Errorf("oracle cloud returned status %d at offset %d", resp.StatusCode(), offset)
 		}
 
 		result := resp.Result().(*OracleCloudJobListResponse)
@@ -493,32 +490,30 @@ func listJobsAndStartDetailsScrape(
 			"offset", offset,
 			"jobs_in_page", len(requisitionList),
 			"total_jobs", totalJobs)
+		page++
+		sink.PageFetched(company, page, len(requisitionList))
 
 		if len(requisitionList) == 0 {
 			slog.Info("[OracleCloud_Scraper] No more jobs found, stopping pagination", "company", company.Name)
 			break
 		}
 
-		allJobsNotToday := true
+		allJobsTooOld := true
 		jobsScrapedInPage := 0
 		for _, posting := range requisitionList {
 			jobPostDate := parseOracleDate(posting.PostedDate)
 
-			// Check if job is from today using centralized function
-			if common.IsJobFromToday(jobPostDate) {
-				allJobsNotToday = false
-			}
-
 			// Check if we should scrape this job using centralized function
 			if common.ShouldScrapeJob(jobPostDate, scrapeDateLimitTruncated) {
+				allJobsTooOld = false
 				jobsScrapedInPage++
-				// Send to worker for detailed scraping
-				jobDetailScrapeChannel <- &jobDetailRequest{
-					requisition: &posting,
-					company:     company,
-					baseURL:     baseURL,
-					siteNumber:  siteNumber,
-				}
+				jobsFound++
+				// Queue for detailed scraping, the details request is the only source of the rest
+				sink.JobQueued(company, &db.Jobs{
+					JobId:       posting.Id,
+					JobRole:     posting.Title,
+					CompanyName: company.Name,
+				}, nil)
 			}
 		}
 
@@ -529,9 +524,9 @@ func listJobsAndStartDetailsScrape(
 				"offset", offset)
 		}
 
-		// Stop if we've seen a full page without any jobs from today
-		if allJobsNotToday {
-			slog.Info("[OracleCloud_Scraper] Full page without today's jobs, stopping pagination", "company", company.Name)
+		// Postings are sorted newest first, stop once a whole page is outside the scrape window
+		if allJobsTooOld {
+			slog.Info("[OracleCloud_Scraper] Full page outside scrape window, stopping pagination", "company", company.Name)
 			break
 		}
 
@@ -543,35 +538,11 @@ func listJobsAndStartDetailsScrape(
 
 		offset += len(requisitionList)
 	}
+
+	return jobsFound, nil
 }
 
-func (ocs OracleCloudScraper) StartScraping(companiesToScrape <-chan db.Companies, scrapeDayLimit time.Time) {
+func (ocs OracleCloudScraper) ListJobs(ctx context.Context, company db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) (int, error) {
 	// Get date at midnight using centralized function
-	scrapeDateLimitTruncated := common.GetDateMidnight(scrapeDayLimit)
-
-	slog.Info("[OracleCloud_Scraper] Starting Oracle Cloud scraper")
-
-	// Create channel for job details scraping
-	jobDetailScrapeChannel := make(chan *jobDetailRequest, 10000)
-	slog.Info("[OracleCloud_Scraper] Oracle Cloud Jobs Details channel created")
-
-	// Start worker pool - workers will watch the channel until it's closed
-	scraperWorkerCount := 4
-	for range make([]struct{}, scraperWorkerCount) {
-		go ocs.jobDetailsScraperWorker(jobDetailScrapeChannel)
-	}
-	slog.Info("[OracleCloud_Scraper] Started workers", "count", scraperWorkerCount)
-
-	// Use WaitGroup to track company listing workers
-	var wg sync.WaitGroup
-	for company := range companiesToScrape {
-		wg.Go(func() {
-			listJobsAndStartDetailsScrape(company, scrapeDateLimitTruncated, jobDetailScrapeChannel)
-		})
-	}
-
-	// Wait for all companies to finish listing jobs, then close the channel
-	wg.Wait()
-	close(jobDetailScrapeChannel)
-	slog.Info("[OracleCloud_Scraper] Oracle Cloud companies job scraping complete.")
+	return listJobs(ctx, company, common.GetDateMidnight(scrapeDayLimit), sink)
 }
@@ -0,0 +1,46 @@
+package oraclecloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/types"
+	"net/url"
+	"strings"
+)
+
+func init() {
+	scraper.Register(scraper.Provider{
+		Name:            types.OracleCloud,
+		New:             func() scraper.Scraper { return OracleCloudScraper{} },
+		ValidatePayload: validatePayload,
+		NormaliseURL:    normaliseURL,
+		MatchesURL: func(u *url.URL) bool {
+			return strings.Contains(u.Path, "/hcmUI/CandidateExperience/")
+		},
+		URLPatterns: "path contains /hcmUI/CandidateExperience/",
+	})
+}
+
+func normaliseURL(browserURL string) (scraper.CompanySource, error) {
+	apiURL, err := TransformBrowserURLToAPIURL(browserURL)
+	if err != nil {
+		return scraper.CompanySource{}, err
+	}
+	return scraper.CompanySource{BaseUrl: apiURL}, nil
+}
+
+func validatePayload(payload []byte) (scraper.CompanySource, error) {
+	var oracleCloudCompData api_models.AddOracleCloudCompanyScrapeList
+	if err := json.Unmarshal(payload, &oracleCloudCompData); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	// Transform browser URL to API URL
+	source, err := normaliseURL(oracleCloudCompData.BrowserUrl)
+	if err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("failed to transform browser_url: %w", err)
+	}
+	return source, nil
+}
@@ -0,0 +1,14 @@
+// Package providers registers every scraper provider with the scraper registry.
+// Import it for its side effects wherever the registry is used.
+package providers
+
+import (
+	_ "job-scraper/internal/scraper/ashby"
+	_ "job-scraper/internal/scraper/custom"
+	_ "job-scraper/internal/scraper/greenhouse"
+	_ "job-scraper/internal/scraper/jsonld"
+	_ "job-scraper/internal/scraper/lever"
+	_ "job-scraper/internal/scraper/oraclecloud"
+	_ "job-scraper/internal/scraper/smartrecruiters"
+	_ "job-scraper/internal/scraper/workday"
+)
@@ -0,0 +1,91 @@
+package scraper
+
+import (
+	"fmt"
+	"job-scraper/internal/types"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// CompanySource is what a provider needs to scrape a company, stored on db.Companies
+type CompanySource struct {
+	BaseUrl        string
+	ApiRequestBody string
+}
+
+// Provider describes a scraper provider. Each provider package registers itself from init(),
+// the factory, the scrape dispatch, the add-company route and auto-detection all read the registry.
+type Provider struct {
+	Name types.ScrapableWebsites
+	// New returns the scraper for the provider's companies
+	New func() Scraper
+	// ValidatePayload checks the provider's add-company request body and builds the source
+	ValidatePayload func(payload []byte) (CompanySource, error)
+	// ValidateRequestBody checks an api_request_body set on an existing company and builds the
+	// source it makes, nil if the provider doesn't use the body
+	ValidateRequestBody func(body []byte) (CompanySource, error)
+	// NormaliseURL translates a careers or board URL to the source, nil if the provider
+	// can't be set up from a URL alone
+	NormaliseURL func(rawURL string) (CompanySource, error)
+	// MatchesURL recognises the provider's URLs for auto-detection, nil to opt out
+	MatchesURL func(u *url.URL) bool
+	// URLPatterns describes the URLs MatchesURL recognises
+	URLPatterns string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[types.ScrapableWebsites]Provider)
+)
+
+// Register adds a provider to the registry, it panics on incomplete or duplicate providers
+// since those are programming errors caught at startup
+func Register(provider Provider) {
+	if provider.Name == "" || provider.New == nil || provider.ValidatePayload == nil {
+		panic("scraper: provider must have a name, a constructor and a payload validator")
+	}
+	if provider.MatchesURL != nil && provider.NormaliseURL == nil {
+		panic(fmt.Sprintf("scraper: provider %s matches URLs but has no URL normaliser", provider.Name))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[provider.Name]; exists {
+		panic(fmt.Sprintf("scraper: provider %s registered twice", provider.Name))
+	}
+	registry[provider.Name] = provider
+}
+
+// Lookup returns the registered provider with the given name
+func Lookup(name types.ScrapableWebsites) (Provider, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	provider, ok := registry[name]
+	return provider, ok
+}
+
+// Providers returns every registered provider sorted by name
+func Providers() []Provider {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	providers := make([]Provider, 0, len(registry))
+	for _, provider := range registry {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+	return providers
+}
+
+// ProviderNames returns the names of every registered provider sorted by name
+func ProviderNames() []string {
+	providers := Providers()
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		names[i] = string(provider.Name)
+	}
+	return names
+}
@@ -0,0 +1,41 @@
+package smartrecruiters
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/scraper/common"
+	"job-scraper/internal/types"
+)
+
+func init() {
+	scraper.Register(scraper.Provider{
+		Name:            types.SmartRecruiters,
+		New:             func() scraper.Scraper { return SmartRecruitersScraper{} },
+		ValidatePayload: validatePayload,
+		NormaliseURL:    normaliseURL,
+		MatchesURL:      common.HostIs("jobs.smartrecruiters.com", "careers.smartrecruiters.com", "api.smartrecruiters.com"),
+		URLPatterns:     "host is jobs.smartrecruiters.com, careers.smartrecruiters.com or api.smartrecruiters.com",
+	})
+}
+
+func normaliseURL(boardURL string) (scraper.CompanySource, error) {
+	apiURL, err := TransformBoardURLToAPIURL(boardURL)
+	if err != nil {
+		return scraper.CompanySource{}, err
+	}
+	return scraper.CompanySource{BaseUrl: apiURL}, nil
+}
+
+func validatePayload(payload []byte) (scraper.CompanySource, error) {
+	var smartrecruitersCompData api_models.AddSmartRecruitersCompanyScrapeList
+	if err := json.Unmarshal(payload, &smartrecruitersCompData); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	if smartrecruitersCompData.CompanyIdentifier == "" {
+		return scraper.CompanySource{}, fmt.Errorf("company_identifier is required")
+	}
+	return scraper.CompanySource{BaseUrl: BuildPostingsURL(smartrecruitersCompData.CompanyIdentifier)}, nil
+}
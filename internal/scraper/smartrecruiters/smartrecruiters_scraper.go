@@ -0,0 +1,299 @@
+package smartrecruiters
+
+import (
+	"context"
+	"fmt"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper/common"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/k3a/html2text"
+	"resty.dev/v3"
+)
+
+type SmartRecruitersListJobs struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	RefNumber    string `json:"refNumber"`
+	ReleasedDate string `json:"releasedDate"`
+	Ref          string `json:"ref"`
+}
+
+type SmartRecruitersResponse struct {
+	Offset     int                       `json:"offset"`
+	Limit      int                       `json:"limit"`
+	TotalFound int                       `json:"totalFound"`
+	Content    []SmartRecruitersListJobs `json:"content"`
+}
+
+type SmartRecruitersJobAdSection struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+type SmartRecruitersJobAdSections struct {
+	CompanyDescription    SmartRecruitersJobAdSection `json:"companyDescription"`
+	JobDescription        SmartRecruitersJobAdSection `json:"jobDescription"`
+	Qualifications        SmartRecruitersJobAdSection `json:"qualifications"`
+	AdditionalInformation SmartRecruitersJobAdSection `json:"additionalInformation"`
+}
+
+type SmartRecruitersJobDetailsResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	RefNumber  string `json:"refNumber"`
+	PostingUrl string `json:"postingUrl"`
+	JobAd      struct {
+		Sections SmartRecruitersJobAdSections `json:"sections"`
+	} `json:"jobAd"`
+}
+
+type SmartRecruitersScraper struct{}
+
+// SmartRecruiters caps the postings page size at 100
+const smartRecruitersPageSize = 100
+
+// BuildPostingsURL returns the SmartRecruiters postings API URL for a company identifier
+// Example: Visa -> https://api.smartrecruiters.com/v1/companies/Visa/postings
+func BuildPostingsURL(companyIdentifier string) string {
+	return fmt.Sprintf("https://api.smartrecruiters.com/v1/companies/%s/postings", strings.Trim(strings.TrimSpace(companyIdentifier), "/"))
+}
+
+// TransformBoardURLToAPIURL converts a SmartRecruiters careers URL to the postings API URL
+// Example: https://jobs.smartrecruiters.com/Visa -> https://api.smartrecruiters.com/v1/companies/Visa/postings
+func TransformBoardURLToAPIURL(boardURL string) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(boardURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	pathParts := common.SplitURLPath(parsedURL.Path)
+
+	var companyIdentifier string
+	switch strings.ToLower(parsedURL.Host) {
+	case "jobs.smartrecruiters.com", "careers.smartrecruiters.com":
+		// Format: https://jobs.smartrecruiters.com/{companyIdentifier}/{posting_id}
+		if len(pathParts) > 0 {
+			companyIdentifier = pathParts[0]
+		}
+	case "api.smartrecruiters.com":
+		// Format: https://api.smartrecruiters.com/v1/companies/{companyIdentifier}/postings
+		if len(pathParts) >= 3 && pathParts[1] == "companies" {
+			companyIdentifier = pathParts[2]
+		}
+	default:
+		return "", fmt.Errorf("not a SmartRecruiters careers URL: %s", parsedURL.Host)
+	}
+
+	if companyIdentifier == "" {
+		return "", fmt.Errorf("could not extract company identifier from URL path")
+	}
+
+	return BuildPostingsURL(companyIdentifier), nil
+}
+
+// postingLink returns the public link of a posting. Without a postingUrl it's built from the
+// posting's API URL (ref), or the ref is kept so the job doesn't get an empty link and hash.
+// Example: https://api.smartrecruiters.com/v1/companies/Visa/postings/123 -> https://jobs.smartrecruiters.com/Visa/123
+func postingLink(postingURL string, ref string) string {
+	if postingURL != "" {
+		return postingURL
+	}
+
+	parsedURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	pathParts := common.SplitURLPath(parsedURL.Path)
+	if len(pathParts) >= 5 && pathParts[1] == "companies" && pathParts[3] == "postings" {
+		return fmt.Sprintf("https://jobs.smartrecruiters.com/%s/%s", pathParts[2], pathParts[4])
+	}
+	return ref
+}
+
+func parseSmartRecruitersDate(dateStr string) (time.Time, error) {
+	// Parse ISO 8601 format: "2025-10-29T14:01:33.604Z"
+	return time.Parse(time.RFC3339Nano, dateStr)
+}
+
+// buildJobAdDescription joins the job ad sections in the order they are shown on the posting page
+func buildJobAdDescription(sections SmartRecruitersJobAdSections) string {
+	var sb strings.Builder
+	for _, section := range []SmartRecruitersJobAdSection{
+		sections.JobDescription,
+		sections.Qualifications,
+		sections.AdditionalInformation,
+		sections.CompanyDescription,
+	} {
+		if section.Text == "" {
+			continue
+		}
+		sb.WriteString("<h3>" + section.Title + "</h3>")
+		sb.WriteString(section.Text)
+	}
+
+	return common.RemoveExtraNewlines(common.CleanUTF8String(html2text.HTML2Text(sb.String())))
+}
+
+// pageBeforeWindow reports whether listing can stop after a page, given the posting dates parsed
+// from it in order. The API doesn't document its ordering, so it only stops when the page itself
+// is newest first and every posting on it is older than the scrape window. Postings whose date
+// didn't parse aren't in postDates and never stop the listing.
+func pageBeforeWindow(postDates []time.Time, scrapeDateLimit time.Time) bool {
+	if len(postDates) == 0 {
+		return false
+	}
+	for i, postDate := range postDates {
+		if common.ShouldScrapeJob(postDate, scrapeDateLimit) {
+			return false
+		}
+		if i > 0 && postDate.After(postDates[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Shared by every job details request, resty clients are safe for concurrent use
+var detailClient = resty.New().SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+
+func listJobs(ctx context.Context, company db.Companies, scrapeDateLimitTruncated time.Time, sink common.ScrapeSink) (int, error) {
+	rClient := resty.New()
+	rClient.SetHeader("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+	defer rClient.Close()
+
+	// BaseUrl should be in format: https://api.smartrecruiters.com/v1/companies/{companyIdentifier}/postings
+	if company.BaseUrl == "" {
+		return 0, fmt.Errorf("base URL not found for company")
+	}
+
+	jobsFound := 0
+	offset := 0
+	page := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return jobsFound, err
+		}
+
+		var listResp SmartRecruitersResponse
+
+		resp, err := rClient.R().
+			SetContext(ctx).
+			SetHeaders(map[string]string{
+				"Accept":        "application/json",
+				"cache-control": "no-cache",
+			}).
+			SetQueryParams(map[string]string{
+				"offset": fmt.Sprintf("%d", offset),
+				"limit":  fmt.Sprintf("%d", smartRecruitersPageSize),
+			}).
+			SetResult(&listResp).
+			Get(company.BaseUrl)
+
+		if err != nil {
+			return jobsFound, fmt.Errorf("failed to fetch jobs at offset %d: %w", offset, err)
+		}
+
+		if resp.IsError() {
+			return jobsFound, fmt.Errorf("smartrecruiters returned status %d at offset %d", resp.StatusCode(), offset)
+		}
+
+		result := resp.Result().(*SmartRecruitersResponse)
+
+		slog.Info("[SmartRecruiters_Scraper] Successfully fetched jobs", "company", company.Name, "offset", offset, "jobs_in_response", len(result.Content))
+		page++
+		sink.PageFetched(company, page, len(result.Content))
+
+		if len(result.Content) == 0 {
+			slog.Info("[SmartRecruiters_Scraper] No more jobs found, stopping pagination", "company", company.Name)
+			break
+		}
+
+		var postDates []time.Time
+		for _, posting := range result.Content {
+			jobPostDate, err := parseSmartRecruitersDate(posting.ReleasedDate)
+			if err != nil {
+				slog.Error("[SmartRecruiters_Scraper] Failed to parse releasedDate", "date", posting.ReleasedDate, "error", err)
+				sink.Warning(company, fmt.Sprintf("skipped job %s: failed to parse releasedDate %q", posting.Ref, posting.ReleasedDate))
+				continue
+			}
+			postDates = append(postDates, jobPostDate)
+
+			// Check if we should scrape this job using centralized function
+			if common.ShouldScrapeJob(jobPostDate, scrapeDateLimitTruncated) {
+				jobsFound++
+				job := &db.Jobs{
+					JobHash:      "",
+					JobId:        posting.RefNumber,
+					JobRole:      posting.Name,
+					JobDetails:   "",
+					JobPostDate:  jobPostDate.Format("2006-01-02"),
+					JobLink:      posting.Ref,
+					JobAISummary: "",
+					CompanyName:  company.Name,
+				}
+
+				sink.JobQueued(company, job, nil)
+			}
+		}
+		offset += len(result.Content)
+		if offset >= result.TotalFound {
+			slog.Info("[SmartRecruiters_Scraper] Reached end of job listings", "company", company.Name)
+			break
+		}
+		if pageBeforeWindow(postDates, scrapeDateLimitTruncated) {
+			slog.Info("[SmartRecruiters_Scraper] Page is older than the scrape window, stopping pagination", "company", company.Name, "offset", offset, "total_found", result.TotalFound)
+			break
+		}
+	}
+
+	return jobsFound, nil
+}
+
+func (srs SmartRecruitersScraper) ListJobs(ctx context.Context, company db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) (int, error) {
+	// Get date at midnight using centralized function
+	return listJobs(ctx, company, common.GetDateMidnight(scrapeDayLimit), sink)
+}
+
+func (srs SmartRecruitersScraper) ScrapeJob(ctx context.Context, queued common.QueuedJob, scrapeDayLimit time.Time, sink common.ScrapeSink) error {
+	job := queued.Job
+
+	// JobLink holds the posting API URL (ref) until the details are fetched
+	var jobDetailsResp SmartRecruitersJobDetailsResponse
+	resp, err := detailClient.R().
+		SetContext(ctx).
+		SetHeaders(map[string]string{
+			"Accept":        "application/json",
+			"cache-control": "no-cache",
+		}).
+		SetResult(&jobDetailsResp).
+		Get(job.JobLink)
+	if err != nil {
+		return fmt.Errorf("failed to fetch job details: %w", err)
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("smartrecruiters returned status %d", resp.StatusCode())
+	}
+
+	// Get the parsed result
+	result := resp.Result().(*SmartRecruitersJobDetailsResponse)
+
+	// Update job details
+	if result.RefNumber != "" {
+		job.JobId = result.RefNumber
+	} else {
+		job.JobId = result.ID
+	}
+	job.JobLink = postingLink(result.PostingUrl, job.JobLink)
+	job.JobDetails = buildJobAdDescription(result.JobAd.Sections)
+	job.JobHash = common.GetSHA256Hash(job.JobLink)
+
+	sink.JobScraped(job)
+
+	slog.Info("[SmartRecruiters_Scraper_Worker] Job Scraped", "JobLink", job.JobLink)
+	return nil
+}
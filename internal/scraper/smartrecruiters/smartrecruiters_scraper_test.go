@@ -0,0 +1,252 @@
+package smartrecruiters
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Note: GetSHA256Hash, CleanUTF8String, and RemoveExtraNewlines tests
+// are in job-scraper/internal/scraper/common/utils_test.go
+// This test file only contains SmartRecruiters-specific function tests.
+
+func TestBuildPostingsURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		expected   string
+	}{
+		{
+			name:       "Plain company identifier",
+			identifier: "Visa",
+			expected:   "https://api.smartrecruiters.com/v1/companies/Visa/postings",
+		},
+		{
+			name:       "Identifier with whitespace and slashes",
+			identifier: " /Visa/ ",
+			expected:   "https://api.smartrecruiters.com/v1/companies/Visa/postings",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BuildPostingsURL(tt.identifier)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseSmartRecruitersDate(t *testing.T) {
+	tests := []struct {
+		name        string
+		dateStr     string
+		expectError bool
+		expected    time.Time
+	}{
+		{
+			name:        "Fractional seconds in UTC",
+			dateStr:     "2025-10-29T14:01:33.604Z",
+			expectError: false,
+			expected:    time.Date(2025, 10, 29, 14, 1, 33, 604000000, time.UTC),
+		},
+		{
+			name:        "No fractional seconds",
+			dateStr:     "2025-10-29T14:01:33Z",
+			expectError: false,
+			expected:    time.Date(2025, 10, 29, 14, 1, 33, 0, time.UTC),
+		},
+		{
+			name:        "Invalid date format",
+			dateStr:     "29/10/2025",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseSmartRecruitersDate(tt.dateStr)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBuildJobAdDescription(t *testing.T) {
+	sections := SmartRecruitersJobAdSections{
+		CompanyDescription:    SmartRecruitersJobAdSection{Title: "Company Description", Text: "<p>About us.</p>"},
+		JobDescription:        SmartRecruitersJobAdSection{Title: "Job Description", Text: "<p>Build things.</p>"},
+		Qualifications:        SmartRecruitersJobAdSection{Title: "Qualifications", Text: "<ul><li>Go</li></ul>"},
+		AdditionalInformation: SmartRecruitersJobAdSection{Title: "Additional Information", Text: ""},
+	}
+
+	result := buildJobAdDescription(sections)
+
+	jobIdx := strings.Index(result, "Build things.")
+	qualIdx := strings.Index(result, "Go")
+	companyIdx := strings.Index(result, "About us.")
+	if jobIdx == -1 || qualIdx == -1 || companyIdx == -1 {
+		t.Fatalf("Expected all non-empty sections in description, got %q", result)
+	}
+	if !(jobIdx < qualIdx && qualIdx < companyIdx) {
+		t.Errorf("Expected job description, qualifications, company description order, got %q", result)
+	}
+	if strings.Contains(result, "Additional Information") {
+		t.Errorf("Expected empty sections to be skipped, got %q", result)
+	}
+}
+
+func TestTransformBoardURLToAPIURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		boardURL    string
+		expectedURL string
+		expectError bool
+	}{
+		{
+			name:        "Jobs URL",
+			boardURL:    "https://jobs.smartrecruiters.com/Visa",
+			expectedURL: "https://api.smartrecruiters.com/v1/companies/Visa/postings",
+		},
+		{
+			name:        "Careers URL with posting",
+			boardURL:    "https://careers.smartrecruiters.com/Visa/744000012345678-engineer",
+			expectedURL: "https://api.smartrecruiters.com/v1/companies/Visa/postings",
+		},
+		{
+			name:        "Already an API URL",
+			boardURL:    "https://api.smartrecruiters.com/v1/companies/Visa/postings?limit=100",
+			expectedURL: "https://api.smartrecruiters.com/v1/companies/Visa/postings",
+		},
+		{
+			name:        "Missing company identifier",
+			boardURL:    "https://jobs.smartrecruiters.com/",
+			expectError: true,
+		},
+		{
+			name:        "Not a SmartRecruiters URL",
+			boardURL:    "https://jobs.ashbyhq.com/visa",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TransformBoardURLToAPIURL(tt.boardURL)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if result != tt.expectedURL {
+				t.Errorf("Expected %q, got %q", tt.expectedURL, result)
+			}
+		})
+	}
+}
+
+func TestPageBeforeWindow(t *testing.T) {
+	limit := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time {
+		return time.Date(2025, 10, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		postDates []time.Time
+		limit     time.Time
+		expected  bool
+	}{
+		{
+			name:      "Newest first page older than the window",
+			postDates: []time.Time{day(15), day(12), day(10)},
+			limit:     limit,
+			expected:  true,
+		},
+		{
+			name:      "Page with a posting inside the window",
+			postDates: []time.Time{day(21), day(15)},
+			limit:     limit,
+			expected:  false,
+		},
+		{
+			name:      "Unsorted page older than the window",
+			postDates: []time.Time{day(10), day(15), day(12)},
+			limit:     limit,
+			expected:  false,
+		},
+		{
+			name:      "Page without any parsed date",
+			postDates: nil,
+			limit:     limit,
+			expected:  false,
+		},
+		{
+			name:      "Backfill never stops",
+			postDates: []time.Time{day(15), day(10)},
+			limit:     time.Time{},
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := pageBeforeWindow(tt.postDates, tt.limit)
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPostingLink(t *testing.T) {
+	tests := []struct {
+		name       string
+		postingURL string
+		ref        string
+		expected   string
+	}{
+		{
+			name:       "Posting URL",
+			postingURL: "https://jobs.smartrecruiters.com/Visa/123-backend-engineer",
+			ref:        "https://api.smartrecruiters.com/v1/companies/Visa/postings/123",
+			expected:   "https://jobs.smartrecruiters.com/Visa/123-backend-engineer",
+		},
+		{
+			name:     "Built from the ref",
+			ref:      "https://api.smartrecruiters.com/v1/companies/Visa/postings/123",
+			expected: "https://jobs.smartrecruiters.com/Visa/123",
+		},
+		{
+			name:     "Unexpected ref kept",
+			ref:      "https://api.smartrecruiters.com/v2/postings/123",
+			expected: "https://api.smartrecruiters.com/v2/postings/123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := postingLink(tt.postingURL, tt.ref)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package workday
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/scraper/common"
+	"job-scraper/internal/types"
+)
+
+func init() {
+	scraper.Register(scraper.Provider{
+		Name:                types.Workday,
+		New:                 func() scraper.Scraper { return WorkdayScraper{} },
+		ValidatePayload:     validatePayload,
+		ValidateRequestBody: validateRequestBody,
+		NormaliseURL:        normaliseURL,
+		MatchesURL:          common.HostHasSuffix(".myworkdayjobs.com", ".myworkdaysite.com"),
+		URLPatterns:         "host ends with .myworkdayjobs.com or .myworkdaysite.com",
+	})
+}
+
+// validateRequestBody checks a CXS search request body and compacts it
+func validateRequestBody(body []byte) (scraper.CompanySource, error) {
+	var searchRequest WorkdaySearchRequest
+	if err := json.Unmarshal(body, &searchRequest); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid search request: %w", err)
+	}
+
+	var compactBuf bytes.Buffer
+	if err := json.Compact(&compactBuf, body); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return scraper.CompanySource{ApiRequestBody: compactBuf.String()}, nil
+}
+
+// normaliseURL translates a careers browser URL to the CXS API URL, the filters selected in
+// the browser (locations, jobFamilyGroup, ...) become the request body facets
+func normaliseURL(browserURL string) (scraper.CompanySource, error) {
+	apiURL, err := TransformBrowserURLToAPIURL(browserURL)
+	if err != nil {
+		return scraper.CompanySource{}, err
+	}
+
+	reqBody, err := BuildRequestBodyFromBrowserURL(browserURL)
+	if err != nil {
+		return scraper.CompanySource{}, err
+	}
+
+	return scraper.CompanySource{BaseUrl: apiURL, ApiRequestBody: reqBody}, nil
+}
+
+func validatePayload(payload []byte) (scraper.CompanySource, error) {
+	var workdayCompData api_models.AddWorkdayCompanyScrapeList
+	if err := json.Unmarshal(payload, &workdayCompData); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	// Translate a browser URL to the CXS API URL, its filters become the request body
+	if workdayCompData.BrowserUrl != "" {
+		source, err := normaliseURL(workdayCompData.BrowserUrl)
+		if err != nil {
+			return scraper.CompanySource{}, fmt.Errorf("failed to transform browser_url: %w", err)
+		}
+		workdayCompData.BaseUrl = source.BaseUrl
+
+		if len(workdayCompData.ApiReqBody) == 0 {
+			workdayCompData.ApiReqBody = []byte(source.ApiRequestBody)
+		}
+	}
+
+	if workdayCompData.BaseUrl == "" {
+		return scraper.CompanySource{}, fmt.Errorf("base_url or browser_url is required")
+	}
+
+	// compact the JSON
+	var compactBuf bytes.Buffer
+	if err := json.Compact(&compactBuf, workdayCompData.ApiReqBody); err != nil {
+		return scraper.CompanySource{}, fmt.Errorf("invalid JSON in req_body")
+	}
+
+	return scraper.CompanySource{
+		BaseUrl:        workdayCompData.BaseUrl,
+		ApiRequestBody: compactBuf.String(),
+	}, nil
+}
@@ -1,15 +1,16 @@
 package workday
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"job-scraper/internal/db"
 	"job-scraper/internal/scraper/common"
 	"log/slog"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/k3a/html2text"
@@ -39,8 +40,8 @@ type WorkdayResponse struct {
 }
 
 func parsePostedDate(postedOn string) time.Time {
-	// Parse "Posted X Days Ago" format
-	re := regexp.MustCompile(`Posted\s+(\d+)\s+Days?\s+Ago`)
+	// Parse "Posted X Days Ago" format, Workday stops counting at "Posted 30+ Days Ago"
+	re := regexp.MustCompile(`Posted\s+(\d+)\+?\s+Days?\s+Ago`)
 	matches := re.FindStringSubmatch(postedOn)
 
 	if len(matches) >= 2 {
@@ -67,7 +68,10 @@ func parsePostedDate(postedOn string) time.Time {
 
 type WorkdayScraper struct{}
 
-func listJobsAndStartDetailsScrape(company db.Companies, scrapeDateLimitTruncated time.Time, jobDetailScrapeChannel chan<- *db.Jobs) {
+// Shared by every job details request, resty clients are safe for concurrent use
+var detailClient = resty.New().SetHeader("User-Agent", "")
+
+func listJobs(ctx context.Context, company db.Companies, scrapeDateLimitTruncated time.Time, sink common.ScrapeSink) (int, error) {
 	rClient := resty.New()
 	rClient.SetHeader("User-Agent", "")
 	defer rClient.Close()
@@ -75,18 +79,25 @@ func listJobsAndStartDetailsScrape(company db.Companies, scrapeDateLimitTruncate
 	var req_body map[string]interface{}
 
 	err := json.Unmarshal([]byte(company.ApiRequestBody), &req_body)
-	if err != nil {
-		slog.Error("error unmarshaling JSON")
+	if err != nil || req_body == nil {
+		return 0, fmt.Errorf("invalid api_request_body: %v", err)
 	}
 	slog.Info("req_body", "req_body", fmt.Sprint(req_body))
 
+	jobsFound := 0
 	offset := 0
+	page := 0
 	for {
+		if err := ctx.Err(); err != nil {
+			return jobsFound, err
+		}
+
 		req_body["offset"] = offset
 		var workdayResp WorkdayResponse
 
 		resp, err := rClient.R().
 			// SetDebug(true).
+			SetContext(ctx).
 			SetHeaders(
 				map[string]string{
 					"cache-control":  "no-cache",
@@ -99,15 +110,19 @@ func listJobsAndStartDetailsScrape(company db.Companies, scrapeDateLimitTruncate
 			Post(company.BaseUrl + "/jobs")
 
 		if err != nil {
-			slog.Error("Failed to fetch jobs", "company", company.Name, "error", err)
-			break
+			return jobsFound, fmt.Errorf("failed to fetch jobs at offset %d: %w", offset, err)
+		}
+
+		if resp.IsError() {
+			return jobsFound, fmt.Errorf("workday returned status %d at offset %d", resp.StatusCode(), offset)
 		}
 
 		result := resp.Result().(*WorkdayResponse)
 
 		// Get the parsed result
 		slog.Info("Successfully fetched jobs", "company", company.Name, "offset", offset, "jobs_in_response", len(result.JobPostings))
-		// break
+		page++
+		sink.PageFetched(company, page, len(result.JobPostings))
 
 		if len(result.JobPostings) == 0 {
 			slog.Info("No more jobs found, stopping pagination", "company", company.Name)
@@ -118,23 +133,24 @@ func listJobsAndStartDetailsScrape(company db.Companies, scrapeDateLimitTruncate
 			jobPostDate := parsePostedDate(posting.PostedOn)
 
 			// Check if we should scrape this job using centralized function
-			if common.ShouldScrapeJob(jobPostDate, scrapeDateLimitTruncated) {
-				allJobsTooOld = false
-				job := &db.Jobs{
-					JobHash:      "",
-					JobId:        "",
-					JobRole:      posting.Title,
-					JobDetails:   "",
-					JobPostDate:  jobPostDate.Format("2006-01-02"),
-					JobLink:      company.BaseUrl + posting.ExternalPath,
-					JobAISummary: "",
-					CompanyName:  company.Name,
-				}
-
-				jobDetailScrapeChannel <- job
-			} else {
-				// Job out of range skipped scraping
+			if !common.ShouldScrapeJob(jobPostDate, scrapeDateLimitTruncated) {
+				continue
 			}
+			allJobsTooOld = false
+			jobsFound++
+
+			job := &db.Jobs{
+				JobHash:      "",
+				JobId:        "",
+				JobRole:      posting.Title,
+				JobDetails:   "",
+				JobPostDate:  jobPostDate.Format("2006-01-02"),
+				JobLink:      company.BaseUrl + posting.ExternalPath,
+				JobAISummary: "",
+				CompanyName:  company.Name,
+			}
+
+			sink.JobQueued(company, job, nil)
 		}
 		if allJobsTooOld {
 			break
@@ -143,72 +159,147 @@ func listJobsAndStartDetailsScrape(company db.Companies, scrapeDateLimitTruncate
 		offset += len(result.JobPostings)
 	}
 
+	return jobsFound, nil
 }
 
-func (ws WorkdayScraper) jobDetailsScraperWorker(jobChannel <-chan *db.Jobs) {
-	slog.Debug("[Workday_Scraper] Worker started to scrape Job Details")
-	rClient := resty.New()
-	rClient.SetHeader("User-Agent", "")
-	defer rClient.Close()
+func (ws WorkdayScraper) ListJobs(ctx context.Context, company db.Companies, scrapeDayLimit time.Time, sink common.ScrapeSink) (int, error) {
+	// Get date at midnight using centralized function
+	return listJobs(ctx, company, common.GetDateMidnight(scrapeDayLimit), sink)
+}
 
-	for job := range jobChannel {
-		var jobDetailsResp WorkdayJobDetailsResponse
-		resp, err := rClient.R().
-			SetHeaders(map[string]string{
-				"cache-control":  "no-cache",
-				"sec-fetch-dest": "document",
-				"sec-fetch-mode": "navigate",
-				"sec-fetch-site": "same-origin",
-			}).
-			SetResult(&jobDetailsResp).
-			Get(job.JobLink)
-		if err != nil {
-			slog.Error("[Workday_Scraper_Worker] Failed to fetch job details", "jobLink", job.JobLink, "error", err)
-			continue
-		}
+func (ws WorkdayScraper) ScrapeJob(ctx context.Context, queued common.QueuedJob, scrapeDayLimit time.Time, sink common.ScrapeSink) error {
+	job := queued.Job
+
+	var jobDetailsResp WorkdayJobDetailsResponse
+	resp, err := detailClient.R().
+		SetContext(ctx).
+		SetHeaders(map[string]string{
+			"cache-control":  "no-cache",
+			"sec-fetch-dest": "document",
+			"sec-fetch-mode": "navigate",
+			"sec-fetch-site": "same-origin",
+		}).
+		SetResult(&jobDetailsResp).
+		Get(job.JobLink)
+	if err != nil {
+		return fmt.Errorf("failed to fetch job details: %w", err)
+	}
 
-		// Get the parsed result
-		result := resp.Result().(*WorkdayJobDetailsResponse)
+	if resp.IsError() {
+		return fmt.Errorf("workday returned status %d", resp.StatusCode())
+	}
+
+	// Get the parsed result
+	result := resp.Result().(*WorkdayJobDetailsResponse)
+
+	// Update job details
+	job.JobId = (result.JobPostingInfo.JobReqId)
+	job.JobLink = (result.JobPostingInfo.ExternalUrl)
+	job.JobDetails = common.RemoveExtraNewlines(common.CleanUTF8String(html2text.HTML2Text(result.JobPostingInfo.JobDescription)))
+	job.JobHash = common.GetSHA256Hash(job.JobLink)
+
+	sink.JobScraped(job)
 
-		// Update job details
-		job.JobId = (result.JobPostingInfo.JobReqId)
-		job.JobLink = (result.JobPostingInfo.ExternalUrl)
-		job.JobDetails = common.RemoveExtraNewlines(common.CleanUTF8String(html2text.HTML2Text(result.JobPostingInfo.JobDescription)))
-		job.JobRole = (job.JobRole)
-		job.CompanyName = (job.CompanyName)
-		job.JobHash = common.GetSHA256Hash(job.JobLink)
+	slog.Info("[Workday_Scraper_Worker] Job Scraped", "JobLink", job.JobLink)
+	return nil
+}
+
+// WorkdaySearchRequest is the body of the CXS /jobs search request
+type WorkdaySearchRequest struct {
+	AppliedFacets map[string][]string `json:"appliedFacets"`
+	Limit         int                 `json:"limit"`
+	Offset        int                 `json:"offset"`
+	SearchText    string              `json:"searchText"`
+}
 
-		// Insert job into database
-		common.InsertJobToDB(job, "Workday_Scraper")
+// Browser query parameters that are not search facets
+var workdayNonFacetParams = map[string]bool{
+	"q":               true,
+	"source":          true,
+	"redirect":        true,
+	"clientRequestID": true,
+}
 
-		slog.Info("[Workday_Scraper_Worker] Job Scraped", "JobLink", job.JobLink)
+var workdayLocaleRegex = regexp.MustCompile(`^[a-z]{2}(-[A-Za-z]{2})?$`)
+
+// TransformBrowserURLToAPIURL converts a Workday careers browser URL to the CXS API base URL
+// Example: https://nvidia.wd5.myworkdayjobs.com/en-US/NVIDIAExternalCareerSite/job/123
+// -> https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite
+func TransformBrowserURLToAPIURL(browserURL string) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(browserURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
 	}
-	slog.Info("[Workday_Scraper_Worker] Job Details Worker shutting down")
+
+	host := strings.ToLower(parsedURL.Host)
+	pathParts := common.SplitURLPath(parsedURL.Path)
+
+	var tenant, site string
+	switch {
+	case len(pathParts) >= 4 && pathParts[0] == "wday" && pathParts[1] == "cxs":
+		// Already an API URL: /wday/cxs/{tenant}/{site}
+		tenant, site = pathParts[2], pathParts[3]
+	case strings.HasSuffix(host, ".myworkdayjobs.com"):
+		// Format: https://{tenant}.wd5.myworkdayjobs.com/{locale?}/{site}
+		tenant = strings.Split(host, ".")[0]
+		for _, part := range pathParts {
+			if !workdayLocaleRegex.MatchString(part) {
+				site = part
+				break
+			}
+		}
+	case strings.HasSuffix(host, ".myworkdaysite.com"):
+		// Format: https://wd3.myworkdaysite.com/{locale?}/recruiting/{tenant}/{site}
+		for i, part := range pathParts {
+			if part == "recruiting" && i+2 < len(pathParts) {
+				tenant, site = pathParts[i+1], pathParts[i+2]
+				break
+			}
+		}
+	default:
+		return "", fmt.Errorf("not a Workday URL: %s", parsedURL.Host)
+	}
+
+	if tenant == "" || site == "" {
+		return "", fmt.Errorf("could not extract tenant and site from URL path")
+	}
+
+	return fmt.Sprintf("https://%s/wday/cxs/%s/%s", parsedURL.Host, tenant, site), nil
 }
 
-func (ws WorkdayScraper) StartScraping(companiesToScrape <-chan db.Companies, scrapeDayLimit time.Time) {
-	// Get date at midnight using centralized function
-	scrapeDateLimitTruncated := common.GetDateMidnight(scrapeDayLimit)
-
-	jobDetailScrapeChannel := make(chan *db.Jobs, 10000)
-	slog.Info("[Workday_Scraper] Workday Jobs Details channel created")
-	scraperWorkerCount := 4
-	for range make([]struct{}, scraperWorkerCount) {
-		go ws.jobDetailsScraperWorker(jobDetailScrapeChannel)
-		slog.Info("[Workday_Scraper] Job details scraper started")
+// BuildRequestBodyFromBrowserURL builds the CXS search request body from the filters of a
+// Workday careers browser URL. Repeated query parameters become facet value lists and "q"
+// becomes the search text.
+// Example: ?locations=abc&locations=def&jobFamilyGroup=xyz
+// -> {"appliedFacets":{"jobFamilyGroup":["xyz"],"locations":["abc","def"]},"limit":20,"offset":0,"searchText":""}
+func BuildRequestBodyFromBrowserURL(browserURL string) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(browserURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	searchRequest := WorkdaySearchRequest{
+		AppliedFacets: map[string][]string{},
+		Limit:         20,
+		Offset:        0,
+		SearchText:    parsedURL.Query().Get("q"),
 	}
 
-	// Use WaitGroup to track company listing workers
-	var wg sync.WaitGroup
-	for company := range companiesToScrape {
-		wg.Go(func() {
-			listJobsAndStartDetailsScrape(company, scrapeDateLimitTruncated, jobDetailScrapeChannel)
-		})
+	for facet, values := range parsedURL.Query() {
+		if workdayNonFacetParams[facet] || strings.HasPrefix(facet, "utm_") {
+			continue
+		}
+		for _, value := range values {
+			if value != "" {
+				searchRequest.AppliedFacets[facet] = append(searchRequest.AppliedFacets[facet], value)
+			}
+		}
 	}
 
-	// Wait for all companies to finish listing jobs, then close the channel
-	wg.Wait()
-	close(jobDetailScrapeChannel)
-	slog.Info("[Workday_Scraper] Workday Companies Job list complete.")
+	reqBody, err := json.Marshal(searchRequest)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request body: %w", err)
+	}
 
+	return string(reqBody), nil
 }
@@ -84,6 +84,15 @@ func TestParsePostedDate(t *testing.T) {
 				return diff >= -12 && diff <= 12
 			},
 		},
+		{
+			name:     "Posted 30+ Days Ago",
+			postedOn: "Posted 30+ Days Ago",
+			checkFn: func(result time.Time) bool {
+				expected := time.Now().AddDate(0, 0, -30)
+				diff := expected.Sub(result).Hours()
+				return diff >= -12 && diff <= 12
+			},
+		},
 		{
 			name:     "Posted 7 Days Ago (with extra spaces)",
 			postedOn: "Posted  7  Days  Ago",
@@ -129,3 +138,187 @@ func TestParsePostedDate(t *testing.T) {
 		})
 	}
 }
+
+func TestTransformBrowserURLToAPIURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		browserURL  string
+		expectedURL string
+		expectError bool
+	}{
+		{
+			name:        "Careers site URL",
+			browserURL:  "https://nvidia.wd5.myworkdayjobs.com/NVIDIAExternalCareerSite",
+			expectedURL: "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite",
+		},
+		{
+			name:        "Careers site URL with locale and job path",
+			browserURL:  "https://nvidia.wd5.myworkdayjobs.com/en-US/NVIDIAExternalCareerSite/job/US-CA-Santa-Clara/Engineer_JR123",
+			expectedURL: "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite",
+		},
+		{
+			name:        "Careers site URL with filters",
+			browserURL:  "https://nvidia.wd5.myworkdayjobs.com/NVIDIAExternalCareerSite?locations=91336993fab910af6d702fae0bb4c2e8",
+			expectedURL: "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite",
+		},
+		{
+			name:        "myworkdaysite recruiting URL",
+			browserURL:  "https://wd3.myworkdaysite.com/en-US/recruiting/acme/External",
+			expectedURL: "https://wd3.myworkdaysite.com/wday/cxs/acme/External",
+		},
+		{
+			name:        "Already an API URL",
+			browserURL:  "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite/jobs",
+			expectedURL: "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite",
+		},
+		{
+			name:        "Missing site",
+			browserURL:  "https://nvidia.wd5.myworkdayjobs.com/en-US",
+			expectError: true,
+		},
+		{
+			name:        "Not a Workday URL",
+			browserURL:  "https://careers.example.com/jobs",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := TransformBrowserURLToAPIURL(tt.browserURL)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if result != tt.expectedURL {
+				t.Errorf("Expected URL:\n%s\nGot:\n%s", tt.expectedURL, result)
+			}
+		})
+	}
+}
+
+func TestBuildRequestBodyFromBrowserURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		browserURL   string
+		expectedBody string
+		expectError  bool
+	}{
+		{
+			name:         "No filters",
+			browserURL:   "https://nvidia.wd5.myworkdayjobs.com/NVIDIAExternalCareerSite",
+			expectedBody: `{"appliedFacets":{},"limit":20,"offset":0,"searchText":""}`,
+		},
+		{
+			name:         "Repeated and single facets",
+			browserURL:   "https://nvidia.wd5.myworkdayjobs.com/NVIDIAExternalCareerSite?locations=abc&locations=def&jobFamilyGroup=xyz",
+			expectedBody: `{"appliedFacets":{"jobFamilyGroup":["xyz"],"locations":["abc","def"]},"limit":20,"offset":0,"searchText":""}`,
+		},
+		{
+			name:         "Search text and tracking parameters",
+			browserURL:   "https://nvidia.wd5.myworkdayjobs.com/en-US/NVIDIAExternalCareerSite?q=software+engineer&source=linkedin&utm_medium=social&timeType=full",
+			expectedBody: `{"appliedFacets":{"timeType":["full"]},"limit":20,"offset":0,"searchText":"software engineer"}`,
+		},
+		{
+			name:         "Empty facet values are dropped",
+			browserURL:   "https://nvidia.wd5.myworkdayjobs.com/NVIDIAExternalCareerSite?locations=",
+			expectedBody: `{"appliedFacets":{},"limit":20,"offset":0,"searchText":""}`,
+		},
+		{
+			name:        "Invalid URL",
+			browserURL:  "https://nvidia.wd5.myworkdayjobs.com/%zz",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := BuildRequestBodyFromBrowserURL(tt.browserURL)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if result != tt.expectedBody {
+				t.Errorf("Expected body:\n%s\nGot:\n%s", tt.expectedBody, result)
+			}
+		})
+	}
+}
+
+func TestValidatePayload(t *testing.T) {
+	tests := []struct {
+		name            string
+		payload         string
+		expectedBaseURL string
+		expectedBody    string
+		expectError     bool
+	}{
+		{
+			name:            "API URL with request body",
+			payload:         `{"name": "NVIDIA", "base_url": "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite", "req_body": {"limit": 20, "offset": 0}}`,
+			expectedBaseURL: "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite",
+			expectedBody:    `{"limit":20,"offset":0}`,
+		},
+		{
+			name:            "Browser URL builds request body",
+			payload:         `{"name": "NVIDIA", "browser_url": "https://nvidia.wd5.myworkdayjobs.com/en-US/NVIDIAExternalCareerSite?locations=abc"}`,
+			expectedBaseURL: "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite",
+			expectedBody:    `{"appliedFacets":{"locations":["abc"]},"limit":20,"offset":0,"searchText":""}`,
+		},
+		{
+			name:            "Browser URL keeps explicit request body",
+			payload:         `{"name": "NVIDIA", "browser_url": "https://nvidia.wd5.myworkdayjobs.com/en-US/NVIDIAExternalCareerSite?locations=abc", "req_body": {"limit": 20}}`,
+			expectedBaseURL: "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite",
+			expectedBody:    `{"limit":20}`,
+		},
+		{
+			name:        "Missing base URL",
+			payload:     `{"name": "NVIDIA", "req_body": {"limit": 20}}`,
+			expectError: true,
+		},
+		{
+			name:        "Missing request body",
+			payload:     `{"name": "NVIDIA", "base_url": "https://nvidia.wd5.myworkdayjobs.com/wday/cxs/nvidia/NVIDIAExternalCareerSite"}`,
+			expectError: true,
+		},
+		{
+			name:        "Invalid browser URL",
+			payload:     `{"name": "NVIDIA", "browser_url": "https://jobs.lever.co/netflix"}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := validatePayload([]byte(tt.payload))
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.BaseUrl != tt.expectedBaseURL {
+				t.Errorf("Expected base URL %q, got %q", tt.expectedBaseURL, result.BaseUrl)
+			}
+			if result.ApiRequestBody != tt.expectedBody {
+				t.Errorf("Expected request body %q, got %q", tt.expectedBody, result.ApiRequestBody)
+			}
+		})
+	}
+}
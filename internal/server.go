@@ -1,12 +1,19 @@
 package internal
 
 import (
+	"context"
+	"errors"
 	"job-scraper/internal/config"
 	"job-scraper/internal/db"
+	"job-scraper/internal/services/service_schedule"
+	"job-scraper/internal/services/service_scraper"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"log/slog"
 
@@ -28,7 +35,18 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return cv.validator.Struct(i)
 }
 
-func StartServer() {
+// Roles a process can run as, set with --role
+const (
+	// Serves the API, schedules scrapes and records their runs, leaving the scraping to workers
+	RoleAPI = "api"
+	// Works the tasks of the scrape runs started by an API process
+	RoleWorker = "worker"
+	// Serves the API and works its own scrape runs
+	RoleAll = "all"
+)
+
+// StartServer runs the process in the given role until it receives SIGINT or SIGTERM
+func StartServer(role string) {
 	// Configure log level based on environment variable
 	logLevel := slog.LevelInfo // default to Info
 	if os.Getenv("LOG_LEVEL") == "debug" || os.Getenv("LOG_LEVEL") == "DEBUG" {
@@ -52,64 +70,140 @@ func StartServer() {
 		logger.Error("Database DSN not set in config.yaml")
 		os.Exit(1)
 	}
+	if role != RoleAPI && role != RoleWorker && role != RoleAll {
+		logger.Error("Unknown role", "role", role, "expected", []string{RoleAPI, RoleWorker, RoleAll})
+		os.Exit(1)
+	}
 	db.ConnectDatabase(dsn)
+	secrets := config.GetSecrets()
+
+	e := echo.New()
+	// Middleware
+	// e.Use(echomiddleware.Logger())
+	e.Use(middleware.CORS())
+	e.Use(echomiddleware.Recover())
+	e.Validator = &CustomValidator{validator: validator.New()}
 
+	e.GET("/health", func(c echo.Context) error {
+		slog.Info("Health check endpoint hit")
+		return c.JSON(http.StatusOK, echo.Map{
+			"status": "Healthy",
+		})
+	})
+
+	if role == RoleWorker {
+		// The API process owns the schema, workers only serve their health check
+		go service_scraper.RunWorker()
+	} else {
+		startAPI(e, role == RoleAll, secrets)
+	}
+
+	// Start server
+	port := os.Getenv("PORT")
+	logger.Info("API Port", "port", port)
+	if port == "" {
+		port = "8080"
+	}
+	go func() {
+		if err := e.Start(":" + port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-stop
+	logger.Info("Shutting down", "signal", sig.String(), "role", role, "timeout", secrets.ShutdownTimeout)
+	shutdown(e, role != RoleWorker, secrets.ShutdownTimeout)
+}
+
+// startAPI migrates the schema, starts the scheduler and serves the API routes on e. Scrape runs
+// work their own tasks with localWork, and leave them to worker processes otherwise.
+func startAPI(e *echo.Echo, localWork bool, secrets config.SecretsStruct) {
 	// Install pg_trgm extension for trigram operations
 	if err := db.DB.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
-		logger.Error("Failed to install pg_trgm extension", "error", err)
+		slog.Error("Failed to install pg_trgm extension", "error", err)
 		panic("Extension installation failed")
 	}
-	logger.Info("pg_trgm extension installed")
+	slog.Info("pg_trgm extension installed")
 
 	// Auto-migrate models (add all models here as your app grows)
-	if err := db.DB.AutoMigrate(&db.Companies{}, &db.Jobs{}); err != nil {
-		logger.Error("AutoMigrate failed", "error", err)
+	if err := db.DB.AutoMigrate(&db.Companies{}, &db.Jobs{}, &db.ScrapeRun{}, &db.ScrapeRunCompany{}, &db.ScrapeTask{}); err != nil {
+		slog.Error("AutoMigrate failed", "error", err)
 		panic("Automigration Failed")
 	}
-	logger.Info("Auto Migration Successful")
+	slog.Info("Auto Migration Successful")
 
 	// Create GIN indexes manually for trigram operations
 	if err := db.DB.Exec(`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_job_role_trgm ON jobs USING gin (job_role gin_trgm_ops)`).Error; err != nil {
-		logger.Error("Failed to create GIN index on job_role", "error", err)
+		slog.Error("Failed to create GIN index on job_role", "error", err)
 		panic("Index creation failed")
 	}
-	logger.Info("GIN index on job_role created")
+	slog.Info("GIN index on job_role created")
 
 	if err := db.DB.Exec(`CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_job_details_trgm ON jobs USING gin (job_details gin_trgm_ops)`).Error; err != nil {
-		logger.Error("Failed to create GIN index on job_details", "error", err)
+		slog.Error("Failed to create GIN index on job_details", "error", err)
 		panic("Index creation failed")
 	}
-	logger.Info("GIN index on job_details created")
+	slog.Info("GIN index on job_details created")
+
+	// Start the built-in scheduler, jobs without a cron expression stay unscheduled
+	if err := service_schedule.Start(secrets.ScrapeSchedule, secrets.CleanupSchedule); err != nil {
+		slog.Error("Failed to start scheduler", "error", err)
+		os.Exit(1)
+	}
+
+	// Scrape runs are worked here, or by worker processes whose events are relayed here
+	service_scraper.SetLocalWork(localWork)
+	go service_scraper.RelayWorkerEvents(context.Background())
+
+	// Carry on with the scrape run a previous process was stopped in the middle of, or one a
+	// replica leaves later on
+	service_scraper.ResumeInterruptedRun()
+	go service_scraper.ResumeInterruptedRuns()
 
-	e := echo.New()
-	// Middleware
-	// e.Use(echomiddleware.Logger())
-	e.Use(middleware.CORS())
-	e.Use(echomiddleware.Recover())
-	e.Validator = &CustomValidator{validator: validator.New()}
 	attachPaths(e)
+}
 
-	e.GET("/health", func(c echo.Context) error {
-		slog.Info("Health check endpoint hit")
-		return c.JSON(http.StatusOK, echo.Map{
-			"status": "Healthy",
-		})
-	})
+// Time given to open HTTP requests once the scrapers are done
+const httpShutdownTimeout = 10 * time.Second
 
-	// Start server
-	port := os.Getenv("PORT")
-	logger.Info("API Port", "port", port)
-	if port == "" {
-		port = "8080"
+// shutdown stops the scheduler of an API process, lets scrape runs in progress, or the tasks a
+// worker is on, finish within the timeout and then stops the HTTP server. Scrape triggers are
+// refused from the start, other endpoints keep serving until the scrapers are done.
+func shutdown(e *echo.Echo, scheduled bool, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var schedulerStopped context.Context
+	if scheduled {
+		schedulerStopped = service_schedule.Stop()
+	}
+
+	if err := service_scraper.Shutdown(ctx); err != nil {
+		slog.Error("Scrape runs didn't shut down cleanly", "error", err)
+	}
+
+	// Scheduled scrapes return once their run is recorded
+	if scheduled {
+		select {
+		case <-schedulerStopped.Done():
+		case <-ctx.Done():
+		}
+	}
+
+	httpCtx, httpCancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer httpCancel()
+	if err := e.Shutdown(httpCtx); err != nil {
+		slog.Error("HTTP server didn't shut down cleanly", "error", err)
 	}
-	e.Logger.Fatal(e.Start(":" + port))
+	slog.Info("Shutdown complete")
 }
 
 func attachPaths(e *echo.Echo) {
 	// Public routes
-	e.POST("/add_scrape_company/workday", SubmitWorkdayCompanyToScrape)
-	e.POST("/add_scrape_company/greenhouse", SubmitGreenhouseCompanyToScrape)
-	e.POST("/add_scrape_company/oraclecloud", SubmitOracleCloudCompanyToScrape)
+	e.POST("/add_scrape_company/auto", SubmitAutoDetectedCompanyToScrape)
+	e.POST("/add_scrape_company/:provider", SubmitCompanyToScrape)
 	e.GET("/start_scrape", SubmitScrapeRequest)
 
 	// API routes for frontend
@@ -121,7 +215,14 @@ func attachPaths(e *echo.Echo) {
 	api.GET("/companies", GetCompanies)
 	api.PUT("/companies/:name", UpdateCompany)
 	api.DELETE("/companies/:name", DeleteCompany)
+	api.POST("/companies/preview", PreviewCompany)
+	api.POST("/companies/:name/scrape", ScrapeCompany)
 	api.DELETE("/jobs/cleanup", DeleteOldJobs)
+	api.GET("/scrape_runs", GetScrapeRuns)
+	api.GET("/scrape_runs/:id", GetScrapeRun)
+	api.GET("/scrape_runs/:id/events", StreamScrapeRunEvents)
+	api.POST("/scrape_runs/:id/cancel", CancelScrapeRun)
+	api.GET("/schedule", GetSchedule)
 
 	// Redirect root to /ui
 	e.GET("/", func(c echo.Context) error {
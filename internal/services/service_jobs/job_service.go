@@ -402,12 +402,30 @@ func GetCompanies(c echo.Context) error {
 	companyResponses := make([]api_models.CompanyResponse, len(companies))
 	for i, company := range companies {
 		companyResponses[i] = api_models.CompanyResponse{
-			Name:                 company.Name,
-			BaseUrl:              company.BaseUrl,
-			CareerSiteType:       company.CareerSiteType,
-			ApiRequestBody:       company.ApiRequestBody,
-			ApiRequestQueryParam: company.ApiRequestQueryParam,
-			ToScrape:             company.ToScrape,
+			Name:                  company.Name,
+			BaseUrl:               company.BaseUrl,
+			CareerSiteType:        company.CareerSiteType,
+			ApiRequestBody:        company.ApiRequestBody,
+			ApiRequestQueryParam:  company.ApiRequestQueryParam,
+			ToScrape:              company.ToScrape,
+			ScrapeIntervalMinutes: company.ScrapeIntervalMinutes,
+			Priority:              company.Priority,
+			Healthy:               company.UnhealthySince == nil,
+			LastError:             company.LastError,
+			ConsecutiveFailures:   company.ConsecutiveFailures,
+			LastJobCount:          company.LastJobCount,
+		}
+		if company.LastScrapedAt != nil {
+			lastScrapedAt := company.LastScrapedAt.Format(time.RFC3339)
+			companyResponses[i].LastScrapedAt = &lastScrapedAt
+		}
+		if company.UnhealthySince != nil {
+			unhealthySince := company.UnhealthySince.Format(time.RFC3339)
+			companyResponses[i].UnhealthySince = &unhealthySince
+		}
+		if company.LastSuccessAt != nil {
+			lastSuccessAt := company.LastSuccessAt.Format(time.RFC3339)
+			companyResponses[i].LastSuccessAt = &lastSuccessAt
 		}
 	}
 
@@ -417,15 +435,21 @@ func GetCompanies(c echo.Context) error {
 	})
 }
 
-// DeleteOldJobs deletes jobs older than 10 days based on when they were inserted into the database
-func DeleteOldJobs(c echo.Context) error {
+// CleanupOldJobs deletes jobs inserted into the database more than 10 days ago and returns
+// how many were deleted along with the cutoff used
+func CleanupOldJobs() (int64, time.Time, error) {
 	// Calculate the timestamp 10 days ago
 	tenDaysAgo := time.Now().AddDate(0, 0, -10)
 
 	// Delete jobs inserted more than 10 days ago
 	result := db.DB.Where("job_insert_time < ?", tenDaysAgo).Delete(&db.Jobs{})
+	return result.RowsAffected, tenDaysAgo, result.Error
+}
 
-	if result.Error != nil {
+// DeleteOldJobs deletes jobs older than 10 days based on when they were inserted into the database
+func DeleteOldJobs(c echo.Context) error {
+	deletedCount, cutoff, err := CleanupOldJobs()
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, api_models.StdResponse{
 			Message: "Failed to delete old jobs",
 			Data:    nil,
@@ -433,8 +457,8 @@ func DeleteOldJobs(c echo.Context) error {
 	}
 
 	response := map[string]interface{}{
-		"deleted_count": result.RowsAffected,
-		"cutoff_date":   tenDaysAgo.Format(time.RFC3339),
+		"deleted_count": deletedCount,
+		"cutoff_date":   cutoff.Format(time.RFC3339),
 	}
 
 	return c.JSON(http.StatusOK, api_models.StdResponse{
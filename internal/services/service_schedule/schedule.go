@@ -0,0 +1,165 @@
+package service_schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/db"
+	"job-scraper/internal/services/service_jobs"
+	"job-scraper/internal/services/service_scraper"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/robfig/cron/v3"
+)
+
+// Scheduled job names
+const (
+	JobScrape  = "scrape"
+	JobCleanup = "cleanup"
+)
+
+// scheduledJob is a job registered with the scheduler and the outcome of its last run
+type scheduledJob struct {
+	name     string
+	schedule string
+	entryID  cron.EntryID
+
+	mu         sync.Mutex
+	lastRun    *time.Time
+	lastResult string
+}
+
+func (j *scheduledJob) recordRun(startedAt time.Time, result string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.lastRun = &startedAt
+	j.lastResult = result
+}
+
+var (
+	scheduler *cron.Cron
+	jobs      []*scheduledJob
+)
+
+// slogCronLogger sends the scheduler's own logs to slog
+type slogCronLogger struct{}
+
+func (slogCronLogger) Info(msg string, keysAndValues ...interface{}) {
+	slog.Debug("[Scheduler] "+msg, keysAndValues...)
+}
+
+func (slogCronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	slog.Error("[Scheduler] "+msg, append(keysAndValues, "error", err)...)
+}
+
+// Start schedules the scrape and the cleanup with the given cron expressions, an empty expression
+// leaves its job unscheduled. Expressions use the standard 5 fields or descriptors like @daily.
+func Start(scrapeSchedule, cleanupSchedule string) error {
+	logger := slogCronLogger{}
+	// A job still running when it's due again is skipped, not stacked
+	scheduler = cron.New(cron.WithLogger(logger), cron.WithChain(cron.Recover(logger), cron.SkipIfStillRunning(logger)))
+
+	for _, job := range []struct {
+		name     string
+		schedule string
+		run      func() string
+	}{
+		{JobScrape, scrapeSchedule, runScrape},
+		{JobCleanup, cleanupSchedule, runCleanup},
+	} {
+		if job.schedule == "" {
+			continue
+		}
+
+		scheduled := &scheduledJob{name: job.name, schedule: job.schedule}
+		entryID, err := scheduler.AddFunc(job.schedule, func() {
+			startedAt := time.Now()
+			slog.Info("[Scheduler] Running scheduled job", "job", scheduled.name)
+			scheduled.recordRun(startedAt, job.run())
+		})
+		if err != nil {
+			return fmt.Errorf("invalid %s schedule %q: %w", job.name, job.schedule, err)
+		}
+		scheduled.entryID = entryID
+		jobs = append(jobs, scheduled)
+		slog.Info("[Scheduler] Job scheduled", "job", job.name, "schedule", job.schedule)
+	}
+
+	scheduler.Start()
+	return nil
+}
+
+// Stop stops scheduling jobs, the returned context is done once the jobs already running return
+func Stop() context.Context {
+	return scheduler.Stop()
+}
+
+// runScrape scrapes the companies due for a scrape and waits for the run to finish, so the
+// scheduler skips the next tick of a slow run. Runs started by hand or on another replica share
+// the scrape lock, in which case the tick is skipped.
+func runScrape() string {
+	run, done, err := service_scraper.ScrapeDueCompanies(db.ScrapeTriggerSchedule)
+	if errors.Is(err, service_scraper.ErrScrapeRunInProgress) {
+		slog.Info("[Scheduler] Scrape skipped, a scrape run is already in progress")
+		return "skipped: " + err.Error()
+	}
+	if errors.Is(err, service_scraper.ErrShuttingDown) {
+		return "skipped: " + err.Error()
+	}
+	if err != nil {
+		slog.Error("[Scheduler] Failed to start scrape run", "error", err)
+		return "failed: " + err.Error()
+	}
+	if run == nil {
+		return "skipped: no companies due for scraping"
+	}
+
+	<-done
+	return fmt.Sprintf("run %d %s", run.ID, run.Status)
+}
+
+func runCleanup() string {
+	deletedCount, _, err := service_jobs.CleanupOldJobs()
+	if err != nil {
+		slog.Error("[Scheduler] Failed to delete old jobs", "error", err)
+		return "failed: " + err.Error()
+	}
+	slog.Info("[Scheduler] Deleted old jobs", "deleted_count", deletedCount)
+	return fmt.Sprintf("deleted %d jobs", deletedCount)
+}
+
+// GetSchedule lists the scheduled jobs with their next and last run times
+func GetSchedule(c echo.Context) error {
+	jobResponses := make([]api_models.ScheduledJobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		jobResponse := api_models.ScheduledJobResponse{
+			Name:     job.name,
+			Schedule: job.schedule,
+		}
+
+		if next := scheduler.Entry(job.entryID).Next; !next.IsZero() {
+			nextRun := next.Format(time.RFC3339)
+			jobResponse.NextRun = &nextRun
+		}
+
+		job.mu.Lock()
+		if job.lastRun != nil {
+			lastRun := job.lastRun.Format(time.RFC3339)
+			jobResponse.LastRun = &lastRun
+		}
+		jobResponse.LastResult = job.lastResult
+		job.mu.Unlock()
+
+		jobResponses = append(jobResponses, jobResponse)
+	}
+
+	return c.JSON(http.StatusOK, api_models.StdResponse{
+		Message: "Schedule retrieved successfully",
+		Data:    jobResponses,
+	})
+}
@@ -0,0 +1,56 @@
+package service_scraper
+
+import (
+	"fmt"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/types"
+	"net/url"
+	"strings"
+)
+
+type detectedCompany struct {
+	CareerSiteType types.ScrapableWebsites
+	BaseUrl        string
+	ApiRequestBody string
+}
+
+// describeATSHeuristics lists the URL patterns of every provider taking part in detection,
+// in the order they are tried
+func describeATSHeuristics() []string {
+	var descriptions []string
+	for _, provider := range scraper.Providers() {
+		if provider.MatchesURL != nil {
+			descriptions = append(descriptions, fmt.Sprintf("%s: %s", provider.Name, provider.URLPatterns))
+		}
+	}
+	return descriptions
+}
+
+// detectATS works out which provider serves a careers URL.
+// matched is false when no provider recognised the URL, err is set when one did but the
+// URL couldn't be translated (ex: a Workday URL without a site).
+func detectATS(careersURL string) (company detectedCompany, matched bool, err error) {
+	parsedURL, err := url.Parse(strings.TrimSpace(careersURL))
+	if err != nil || parsedURL.Host == "" {
+		return detectedCompany{}, false, fmt.Errorf("careers_url must be an absolute URL")
+	}
+
+	for _, provider := range scraper.Providers() {
+		if provider.MatchesURL == nil || !provider.MatchesURL(parsedURL) {
+			continue
+		}
+
+		source, err := provider.NormaliseURL(careersURL)
+		if err != nil {
+			return detectedCompany{}, true, fmt.Errorf("%s URL detected but could not be translated: %w", provider.Name, err)
+		}
+
+		return detectedCompany{
+			CareerSiteType: provider.Name,
+			BaseUrl:        source.BaseUrl,
+			ApiRequestBody: source.ApiRequestBody,
+		}, true, nil
+	}
+
+	return detectedCompany{}, false, nil
+}
@@ -0,0 +1,215 @@
+package service_scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/db"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Progress event types streamed for a scrape run
+const (
+	EventCompanyStarted  = "company_started"
+	EventPageFetched     = "page_fetched"
+	EventJobQueued       = "job_queued"
+	EventJobInserted     = "job_inserted"
+	EventJobDuplicate    = "job_duplicate"
+	EventJobFailed       = "job_failed"
+	EventWarning         = "warning"
+	EventCompanyListed   = "company_listed"
+	EventCompanyFinished = "company_finished"
+	EventRunFinished     = "run_finished"
+)
+
+// Events buffered per subscriber, a subscriber that falls further behind misses events
+const runEventBufferSize = 256
+
+// Comment line sent to idle streams so proxies don't close them
+const runEventKeepAlive = 15 * time.Second
+
+// runEventBroker fans the progress events of running scrape runs out to their subscribers
+type runEventBroker struct {
+	mu      sync.Mutex
+	streams map[uint]map[chan api_models.ScrapeRunEvent]struct{}
+}
+
+var scrapeRunEvents = &runEventBroker{
+	streams: make(map[uint]map[chan api_models.ScrapeRunEvent]struct{}),
+}
+
+// open starts accepting subscribers for a run, it must be called before the run publishes
+func (b *runEventBroker) open(runID uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.streams[runID] = make(map[chan api_models.ScrapeRunEvent]struct{})
+}
+
+// subscribe returns the events of a running run, ok is false once the run has finished.
+// The channel is closed after the run's last event.
+func (b *runEventBroker) subscribe(runID uint) (events <-chan api_models.ScrapeRunEvent, unsubscribe func(), ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscribers, ok := b.streams[runID]
+	if !ok {
+		return nil, nil, false
+	}
+
+	ch := make(chan api_models.ScrapeRunEvent, runEventBufferSize)
+	subscribers[ch] = struct{}{}
+
+	unsubscribe = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if subscribers, ok := b.streams[runID]; ok {
+			delete(subscribers, ch)
+		}
+	}
+	return ch, unsubscribe, true
+}
+
+// publish sends an event to every subscriber of its run without blocking the scrapers
+func (b *runEventBroker) publish(event api_models.ScrapeRunEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.streams[event.RunID] {
+		select {
+		case ch <- event:
+		default:
+			slog.Debug("Dropped scrape run event for a slow subscriber", "run", event.RunID, "type", event.Type)
+		}
+	}
+}
+
+// close sends the last event of a run, if any, and ends every subscription to it
+func (b *runEventBroker) close(runID uint, last *api_models.ScrapeRunEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.streams[runID] {
+		if last != nil {
+			select {
+			case ch <- *last:
+			default:
+			}
+		}
+		close(ch)
+	}
+	delete(b.streams, runID)
+}
+
+func writeRunEvent(c echo.Context, event api_models.ScrapeRunEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+	c.Response().Flush()
+	return nil
+}
+
+// StreamScrapeRunEvents streams the progress of a scrape run as Server-Sent Events until the
+// run finishes or the client disconnects. A finished run gets its run_finished event only, a run
+// this process isn't running is refused.
+func StreamScrapeRunEvents(c echo.Context) error {
+	runID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Invalid scrape run id",
+			Data:    nil,
+		})
+	}
+
+	events, unsubscribe, running := scrapeRunEvents.subscribe(uint(runID))
+	if running {
+		defer unsubscribe()
+	} else {
+		var run db.ScrapeRun
+		if err := db.DB.First(&run, runID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return c.JSON(http.StatusNotFound, api_models.StdResponse{
+					Message: fmt.Sprintf("Scrape run %d not found", runID),
+					Data:    nil,
+				})
+			}
+			slog.Error("Failed to fetch scrape run", "error", err, "run", runID)
+			return c.JSON(http.StatusInternalServerError, api_models.StdResponse{
+				Message: "Failed to fetch scrape run",
+				Data:    nil,
+			})
+		}
+
+		// Running in another process, or interrupted and waiting to resume, its events aren't
+		// streamed here
+		if !finishedRunStatus(run.Status) {
+			return c.JSON(http.StatusConflict, api_models.StdResponse{
+				Message: fmt.Sprintf("Scrape run %d is not streamed by this instance, poll GET /api/scrape_runs/%d", run.ID, run.ID),
+				Data:    toScrapeRunResponse(run),
+			})
+		}
+
+		runResponse := toScrapeRunResponse(run)
+		finished := make(chan api_models.ScrapeRunEvent, 1)
+		finished <- api_models.ScrapeRunEvent{
+			Type:   EventRunFinished,
+			RunID:  run.ID,
+			Time:   time.Now().Format(time.RFC3339),
+			Status: run.Status,
+			Run:    &runResponse,
+		}
+		close(finished)
+		events = finished
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "text/event-stream")
+	header.Set(echo.HeaderCacheControl, "no-cache")
+	header.Set(echo.HeaderConnection, "keep-alive")
+	// Keeps nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+
+	keepAlive := time.NewTicker(runEventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(c.Response(), ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			c.Response().Flush()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := writeRunEvent(c, event); err != nil {
+				slog.Debug("Scrape run event stream closed", "run", runID, "error", err)
+				return nil
+			}
+		}
+	}
+}
+
+// finishedRunStatus reports whether a scrape run with status is over
+func finishedRunStatus(status string) bool {
+	switch status {
+	case db.ScrapeRunSucceeded, db.ScrapeRunPartial, db.ScrapeRunFailed, db.ScrapeRunCancelled:
+		return true
+	}
+	return false
+}
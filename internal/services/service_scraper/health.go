@@ -0,0 +1,88 @@
+package service_scraper
+
+import (
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/config"
+	"job-scraper/internal/db"
+	"log/slog"
+	"time"
+)
+
+// recordHealth updates the health of the companies a run finished, cancelled companies keep their
+// health
+func (s *runSink) recordHealth(finished []db.ScrapeRunCompany, now time.Time) {
+	secrets := config.GetSecrets()
+
+	for _, runCompany := range finished {
+		if runCompany.Status == db.ScrapeRunCancelled {
+			continue
+		}
+
+		var company db.Companies
+		if err := db.DB.Where("name = ?", runCompany.CompanyName).Limit(1).Find(&company).Error; err != nil {
+			slog.Error("Failed to fetch company", "company", runCompany.CompanyName, "error", err)
+			continue
+		}
+		// Deleted while it was scraped
+		if company.Name == "" {
+			continue
+		}
+
+		updates, flagged := companyHealthUpdate(company, runCompany, secrets, now)
+		if err := db.DB.Model(&db.Companies{}).Where("name = ?", company.Name).Updates(updates).Error; err != nil {
+			slog.Error("Failed to record company health", "company", company.Name, "error", err)
+			continue
+		}
+
+		if flagged != "" {
+			slog.Warn("Company flagged unhealthy", "company", company.Name, "reason", flagged, "scraping_disabled", secrets.DisableUnhealthy)
+			message := "company flagged unhealthy: " + flagged
+			if secrets.DisableUnhealthy {
+				message += ", scraping turned off"
+			}
+			s.publish(api_models.ScrapeRunEvent{
+				Type:        EventWarning,
+				CompanyName: company.Name,
+				Error:       message,
+			})
+		}
+	}
+}
+
+// companyHealthUpdate returns the health columns of a company to update after a finished scrape,
+// and why the scrape flags it unhealthy, empty unless it's newly flagged. A failed scrape counts
+// towards the failures in a row, a listing that comes back empty after a large one doesn't count
+// as a success, and either can flag the company. A successful scrape clears the flag.
+func companyHealthUpdate(company db.Companies, runCompany db.ScrapeRunCompany, secrets config.SecretsStruct, now time.Time) (updates map[string]interface{}, flagged string) {
+	updates = map[string]interface{}{}
+	var unhealthy string
+	emptied := secrets.UnhealthyMinJobs > 0 && runCompany.JobsSeen == 0 && company.LastJobCount >= secrets.UnhealthyMinJobs
+	switch {
+	case runCompany.Status == db.ScrapeRunFailed:
+		updates["consecutive_failures"] = company.ConsecutiveFailures + 1
+		updates["last_error"] = runCompany.Error
+		if secrets.UnhealthyAfterFailures > 0 && company.ConsecutiveFailures+1 >= secrets.UnhealthyAfterFailures {
+			unhealthy = fmt.Sprintf("%d scrapes failed in a row", company.ConsecutiveFailures+1)
+		}
+	case emptied:
+		// The job count is kept, so the company stays unhealthy until its listing has jobs again
+		unhealthy = fmt.Sprintf("listing came back empty after %d jobs", company.LastJobCount)
+		updates["last_error"] = unhealthy
+	default:
+		updates["last_success_at"] = now
+		updates["last_error"] = ""
+		updates["consecutive_failures"] = 0
+		updates["last_job_count"] = runCompany.JobsSeen
+		updates["unhealthy_since"] = nil
+	}
+
+	if unhealthy == "" || company.UnhealthySince != nil {
+		return updates, ""
+	}
+	updates["unhealthy_since"] = now
+	if secrets.DisableUnhealthy {
+		updates["to_scrape"] = false
+	}
+	return updates, unhealthy
+}
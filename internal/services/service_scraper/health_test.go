@@ -0,0 +1,130 @@
+package service_scraper
+
+import (
+	"job-scraper/internal/config"
+	"job-scraper/internal/db"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompanyHealthUpdate(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	flaggedAt := now.AddDate(0, 0, -2)
+	secrets := config.SecretsStruct{UnhealthyAfterFailures: 3, UnhealthyMinJobs: 10}
+	disabling := config.SecretsStruct{UnhealthyAfterFailures: 3, UnhealthyMinJobs: 10, DisableUnhealthy: true}
+
+	tests := []struct {
+		name            string
+		company         db.Companies
+		runCompany      db.ScrapeRunCompany
+		secrets         config.SecretsStruct
+		expectedUpdates map[string]interface{}
+		expectedFlagged string
+	}{
+		{
+			name:       "Failed below the threshold",
+			company:    db.Companies{ConsecutiveFailures: 1},
+			runCompany: db.ScrapeRunCompany{Status: db.ScrapeRunFailed, Error: "timeout"},
+			secrets:    secrets,
+			expectedUpdates: map[string]interface{}{
+				"consecutive_failures": 2,
+				"last_error":           "timeout",
+			},
+		},
+		{
+			name:       "Failed at the threshold",
+			company:    db.Companies{ConsecutiveFailures: 2},
+			runCompany: db.ScrapeRunCompany{Status: db.ScrapeRunFailed, Error: "timeout"},
+			secrets:    secrets,
+			expectedUpdates: map[string]interface{}{
+				"consecutive_failures": 3,
+				"last_error":           "timeout",
+				"unhealthy_since":      now,
+			},
+			expectedFlagged: "3 scrapes failed in a row",
+		},
+		{
+			name:       "Failed with the threshold turned off",
+			company:    db.Companies{ConsecutiveFailures: 5},
+			runCompany: db.ScrapeRunCompany{Status: db.ScrapeRunFailed, Error: "timeout"},
+			secrets:    config.SecretsStruct{},
+			expectedUpdates: map[string]interface{}{
+				"consecutive_failures": 6,
+				"last_error":           "timeout",
+			},
+		},
+		{
+			name:       "Emptied at the minimum jobs",
+			company:    db.Companies{LastJobCount: 10},
+			runCompany: db.ScrapeRunCompany{Status: db.ScrapeRunSucceeded},
+			secrets:    secrets,
+			expectedUpdates: map[string]interface{}{
+				"last_error":      "listing came back empty after 10 jobs",
+				"unhealthy_since": now,
+			},
+			expectedFlagged: "listing came back empty after 10 jobs",
+		},
+		{
+			name:       "Empty below the minimum jobs is a success",
+			company:    db.Companies{LastJobCount: 9},
+			runCompany: db.ScrapeRunCompany{Status: db.ScrapeRunSucceeded},
+			secrets:    secrets,
+			expectedUpdates: map[string]interface{}{
+				"last_success_at":      now,
+				"last_error":           "",
+				"consecutive_failures": 0,
+				"last_job_count":       0,
+				"unhealthy_since":      nil,
+			},
+		},
+		{
+			name:       "Success clears the flag",
+			company:    db.Companies{ConsecutiveFailures: 4, LastJobCount: 20, UnhealthySince: &flaggedAt},
+			runCompany: db.ScrapeRunCompany{Status: db.ScrapeRunSucceeded, JobsSeen: 25},
+			secrets:    secrets,
+			expectedUpdates: map[string]interface{}{
+				"last_success_at":      now,
+				"last_error":           "",
+				"consecutive_failures": 0,
+				"last_job_count":       25,
+				"unhealthy_since":      nil,
+			},
+		},
+		{
+			name:       "Already flagged isn't flagged again",
+			company:    db.Companies{ConsecutiveFailures: 3, UnhealthySince: &flaggedAt},
+			runCompany: db.ScrapeRunCompany{Status: db.ScrapeRunFailed, Error: "timeout"},
+			secrets:    disabling,
+			expectedUpdates: map[string]interface{}{
+				"consecutive_failures": 4,
+				"last_error":           "timeout",
+			},
+		},
+		{
+			name:       "Flagging turns scraping off",
+			company:    db.Companies{ConsecutiveFailures: 2},
+			runCompany: db.ScrapeRunCompany{Status: db.ScrapeRunFailed, Error: "timeout"},
+			secrets:    disabling,
+			expectedUpdates: map[string]interface{}{
+				"consecutive_failures": 3,
+				"last_error":           "timeout",
+				"unhealthy_since":      now,
+				"to_scrape":            false,
+			},
+			expectedFlagged: "3 scrapes failed in a row",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, flagged := companyHealthUpdate(tt.company, tt.runCompany, tt.secrets, now)
+			if !reflect.DeepEqual(updates, tt.expectedUpdates) {
+				t.Errorf("Expected updates %v, got %v", tt.expectedUpdates, updates)
+			}
+			if flagged != tt.expectedFlagged {
+				t.Errorf("Expected flagged %q, got %q", tt.expectedFlagged, flagged)
+			}
+		})
+	}
+}
@@ -1,140 +1,204 @@
 package service_scraper
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"job-scraper/internal/api_models"
 	"job-scraper/internal/db"
 	"job-scraper/internal/scraper"
-	"job-scraper/internal/scraper/oraclecloud"
+	_ "job-scraper/internal/scraper/providers"
 	"job-scraper/internal/types"
 	"log/slog"
 	"net/http"
-	"time"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
 func StartJobScrapping(c echo.Context) error {
-
-	var companies []db.Companies
-	db.DB.Where(&db.Companies{ToScrape: true}).Order("career_site_type").Find(&companies)
-
-	slog.Info("Starting job scraping session", "total_companies_enabled", len(companies))
-	if len(companies) == 0 {
-		slog.Warn("No companies enabled for scraping")
-		return c.JSON(http.StatusAccepted, api_models.StdResponse{
-			Message: "No companies enabled for scraping",
+	var window api_models.ScrapeWindow
+	if err := c.Bind(&window); err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Invalid scrape window",
 			Data:    nil,
 		})
 	}
 
-	scraper_lister_channels := make(map[types.ScrapableWebsites]chan db.Companies)
+	scrapeDayLimit, err := resolveScrapeWindow(window)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: fmt.Sprintf("Invalid scrape window: %s", err.Error()),
+			Data:    nil,
+		})
+	}
 
-	for _, company := range companies {
-		switch company.CareerSiteType {
-		case string(types.Workday):
-			if scraper_lister_channels[types.Workday] == nil {
-				scraper_lister_channels[types.Workday] = make(chan db.Companies, len(companies))
-				workdayScraper := scraper.JobScraperFactory(types.Workday)
-				go workdayScraper.StartScraping(scraper_lister_channels[types.Workday], time.Now().Truncate(24*time.Hour))
-			}
-			scraper_lister_channels[types.Workday] <- company
-		case string(types.Greenhouse):
-			if scraper_lister_channels[types.Greenhouse] == nil {
-				scraper_lister_channels[types.Greenhouse] = make(chan db.Companies, len(companies))
-				greenhouseScraper := scraper.JobScraperFactory(types.Greenhouse)
-				go greenhouseScraper.StartScraping(scraper_lister_channels[types.Greenhouse], time.Now().Truncate(24*time.Hour))
-			}
-			scraper_lister_channels[types.Greenhouse] <- company
-		case string(types.OracleCloud):
-			if scraper_lister_channels[types.OracleCloud] == nil {
-				scraper_lister_channels[types.OracleCloud] = make(chan db.Companies, len(companies))
-				oraclecloudScraper := scraper.JobScraperFactory(types.OracleCloud)
-				go oraclecloudScraper.StartScraping(scraper_lister_channels[types.OracleCloud], time.Now().Truncate(24*time.Hour))
-			}
-			scraper_lister_channels[types.OracleCloud] <- company
-		default:
-			slog.Debug("This Scraper Logic doesn't exist yet")
-		}
+	run, _, err := ScrapeEnabledCompanies(db.ScrapeTriggerAPI, scrapeDayLimit)
+	var interrupted *InterruptedRunError
+	if errors.As(err, &interrupted) {
+		return interruptedRunResponse(c, interrupted)
+	}
+	if errors.Is(err, ErrScrapeRunInProgress) {
+		return scrapeRunInProgressResponse(c)
+	}
+	if errors.Is(err, ErrShuttingDown) {
+		return shuttingDownResponse(c)
+	}
+	if err != nil {
+		slog.Error("Failed to start scrape run", "error", err)
+		return c.JSON(http.StatusInternalServerError, api_models.StdResponse{
+			Message: "Failed to start scrape run",
+			Data:    nil,
+		})
 	}
 
-	// Close all created channels
-	defer func() {
-		for _, ch := range scraper_lister_channels {
-			close(ch)
-		}
-	}()
+	if run == nil {
+		return c.JSON(http.StatusAccepted, api_models.StdResponse{
+			Message: "No companies enabled for scraping",
+			Data:    nil,
+		})
+	}
 
 	return c.JSON(http.StatusAccepted, api_models.StdResponse{
-		Message: fmt.Sprintf("Scraping started for %d companies", len(companies)),
+		Message: fmt.Sprintf("Scraping started for %d companies", run.CompaniesTotal),
 		Data: map[string]interface{}{
-			"companies_count": len(companies),
+			"companies_count": run.CompaniesTotal,
+			"run_id":          run.ID,
+			"scrape_since":    formatScrapeSince(scrapeDayLimit),
 		},
 	})
 }
 
-func AddWorkdayCompanyToScrapeList(c echo.Context) error {
-	var workdayCompData api_models.AddWorkdayCompanyScrapeList
+// ScrapeCompany scrapes one company through its provider, whether or not it's enabled for
+// scraping, so a new or fixed company can be checked straight away
+func ScrapeCompany(c echo.Context) error {
+	companyName := c.Param("name")
 
-	if err := c.Bind(&workdayCompData); err != nil {
+	var scrapeReq api_models.ScrapeCompanyRequest
+	if err := c.Bind(&scrapeReq); err != nil {
 		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
 			Message: "Invalid request body",
 			Data:    nil,
 		})
 	}
 
-	// compact the JSON
-	var compactBuf bytes.Buffer
-	if err := json.Compact(&compactBuf, workdayCompData.ApiReqBody); err != nil {
+	scrapeDayLimit, err := resolveScrapeWindow(scrapeReq.ScrapeWindow)
+	if err != nil {
 		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
-			Message: "Invalid JSON in req_body",
+			Message: fmt.Sprintf("Invalid scrape window: %s", err.Error()),
 			Data:    nil,
 		})
 	}
 
-	companyDBData := db.Companies{
-		Name:           workdayCompData.Name,
-		BaseUrl:        workdayCompData.BaseUrl,
-		CareerSiteType: string(types.Workday),
-		ApiRequestBody: compactBuf.String(),
-		ToScrape:       true,
+	var company db.Companies
+	if err := db.DB.Where("name = ?", companyName).First(&company).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.JSON(http.StatusNotFound, api_models.StdResponse{
+				Message: fmt.Sprintf("Company '%s' not found", companyName),
+				Data:    nil,
+			})
+		}
+		slog.Error("Failed to fetch company", "error", err, "company", companyName)
+		return c.JSON(http.StatusInternalServerError, api_models.StdResponse{
+			Message: "Failed to fetch company",
+			Data:    nil,
+		})
 	}
 
-	if err := db.DB.Create(&companyDBData).Error; err != nil {
-		slog.Error("Failed to insert company into database",
-			"error", err,
-			"company", companyDBData,
-		)
+	if _, ok := scraper.Lookup(types.ScrapableWebsites(company.CareerSiteType)); !ok {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: fmt.Sprintf("No scraper registered for career_site_type '%s'", company.CareerSiteType),
+			Data:    nil,
+		})
+	}
+
+	run, _, err := StartScrapeRun(ScrapeOptions{
+		Trigger:        db.ScrapeTriggerCompany,
+		Companies:      []db.Companies{company},
+		ScrapeDayLimit: scrapeDayLimit,
+	})
+	var interrupted *InterruptedRunError
+	if errors.As(err, &interrupted) {
+		return interruptedRunResponse(c, interrupted)
+	}
+	if errors.Is(err, ErrScrapeRunInProgress) {
+		return scrapeRunInProgressResponse(c)
+	}
+	if errors.Is(err, ErrShuttingDown) {
+		return shuttingDownResponse(c)
+	}
+	if err != nil {
+		slog.Error("Failed to start scrape run", "error", err, "company", companyName)
 		return c.JSON(http.StatusInternalServerError, api_models.StdResponse{
-			Message: "Failed to insert company.",
+			Message: "Failed to start scrape run",
 			Data:    nil,
 		})
 	}
 
-	slog.Info("Inserted Workday Company to DB.")
 	return c.JSON(http.StatusAccepted, api_models.StdResponse{
-		Message: fmt.Sprintf("Added %s company to scrape list", workdayCompData.Name),
-		Data:    nil,
+		Message: fmt.Sprintf("Scraping started for %s", company.Name),
+		Data: map[string]interface{}{
+			"run_id":       run.ID,
+			"scrape_since": formatScrapeSince(scrapeDayLimit),
+		},
 	})
 }
 
-func AddGreenhouseCompanyToScrapeList(c echo.Context) error {
-	var greenhouseCompData api_models.AddGreenhouseCompanyScrapeList
+// AddCompanyToScrapeList adds a company for the provider named in the path, the provider's
+// registered validator checks the request body and builds the stored base URL and request body
+func AddCompanyToScrapeList(c echo.Context) error {
+	providerName := types.ScrapableWebsites(c.Param("provider"))
+	provider, ok := scraper.Lookup(providerName)
+	if !ok {
+		return c.JSON(http.StatusNotFound, api_models.StdResponse{
+			Message: fmt.Sprintf("Unknown provider '%s'", providerName),
+			Data: map[string]interface{}{
+				"providers": scraper.ProviderNames(),
+			},
+		})
+	}
+
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+	}
 
-	if err := c.Bind(&greenhouseCompData); err != nil {
+	var companyData api_models.AddCompanyScrapeList
+	if err := json.Unmarshal(payload, &companyData); err != nil {
 		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
 			Message: "Invalid request body",
 			Data:    nil,
 		})
 	}
 
+	if companyData.Name == "" {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Company name is required",
+			Data:    nil,
+		})
+	}
+
+	source, err := provider.ValidatePayload(payload)
+	if err != nil {
+		slog.Error("Invalid add company request",
+			"error", err,
+			"provider", provider.Name,
+		)
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: fmt.Sprintf("Invalid %s company: %s", provider.Name, err.Error()),
+			Data:    nil,
+		})
+	}
+
 	companyDBData := db.Companies{
-		Name:           greenhouseCompData.Name,
-		BaseUrl:        greenhouseCompData.BaseUrl,
-		CareerSiteType: string(types.Greenhouse),
+		Name:           companyData.Name,
+		BaseUrl:        source.BaseUrl,
+		CareerSiteType: string(provider.Name),
+		ApiRequestBody: source.ApiRequestBody,
 		ToScrape:       true,
 	}
 
@@ -149,45 +213,61 @@ func AddGreenhouseCompanyToScrapeList(c echo.Context) error {
 		})
 	}
 
-	slog.Info("Inserted Greenhouse Company to DB.")
+	slog.Info("Inserted Company to DB.", "career_site_type", companyDBData.CareerSiteType)
 	return c.JSON(http.StatusAccepted, api_models.StdResponse{
-		Message: fmt.Sprintf("Added %s company to scrape list", greenhouseCompData.Name),
+		Message: fmt.Sprintf("Added %s company to scrape list", companyData.Name),
 		Data:    nil,
 	})
 }
 
-func AddOracleCloudCompanyToScrapeList(c echo.Context) error {
-	var oracleCloudCompData api_models.AddOracleCloudCompanyScrapeList
+func AddAutoDetectedCompanyToScrapeList(c echo.Context) error {
+	var autoCompData api_models.AddAutoDetectedCompanyScrapeList
 
-	if err := c.Bind(&oracleCloudCompData); err != nil {
+	if err := c.Bind(&autoCompData); err != nil {
 		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
 			Message: "Invalid request body",
 			Data:    nil,
 		})
 	}
 
-	// Transform browser URL to API URL
-	apiURL, err := oraclecloud.TransformBrowserURLToAPIURL(oracleCloudCompData.BrowserUrl)
+	if autoCompData.Name == "" {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Company name is required",
+			Data:    nil,
+		})
+	}
+
+	detected, matched, err := detectATS(autoCompData.CareersUrl)
 	if err != nil {
-		slog.Error("Failed to transform Oracle Cloud URL",
+		slog.Error("Failed to detect ATS from careers URL",
 			"error", err,
-			"browserUrl", oracleCloudCompData.BrowserUrl,
+			"careersUrl", autoCompData.CareersUrl,
 		)
 		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
-			Message: fmt.Sprintf("Failed to transform URL: %s", err.Error()),
+			Message: fmt.Sprintf("Failed to detect ATS: %s", err.Error()),
 			Data:    nil,
 		})
 	}
 
+	if !matched {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Could not detect a supported ATS from careers_url",
+			Data: map[string]interface{}{
+				"heuristics_tried": describeATSHeuristics(),
+			},
+		})
+	}
+
 	companyDBData := db.Companies{
-		Name:           oracleCloudCompData.Name,
-		BaseUrl:        apiURL,
-		CareerSiteType: string(types.OracleCloud),
+		Name:           autoCompData.Name,
+		BaseUrl:        detected.BaseUrl,
+		CareerSiteType: string(detected.CareerSiteType),
+		ApiRequestBody: detected.ApiRequestBody,
 		ToScrape:       true,
 	}
 
 	if err := db.DB.Create(&companyDBData).Error; err != nil {
-		slog.Error("Failed to insert Oracle Cloud company into database",
+		slog.Error("Failed to insert auto-detected company into database",
 			"error", err,
 			"company", companyDBData,
 		)
@@ -197,10 +277,13 @@ func AddOracleCloudCompanyToScrapeList(c echo.Context) error {
 		})
 	}
 
-	slog.Info("Inserted Oracle Cloud Company to DB.")
+	slog.Info("Inserted auto-detected Company to DB.", "career_site_type", companyDBData.CareerSiteType)
 	return c.JSON(http.StatusAccepted, api_models.StdResponse{
-		Message: fmt.Sprintf("Added %s company to scrape list", oracleCloudCompData.Name),
-		Data:    nil,
+		Message: fmt.Sprintf("Added %s company to scrape list as %s", autoCompData.Name, companyDBData.CareerSiteType),
+		Data: map[string]interface{}{
+			"career_site_type": companyDBData.CareerSiteType,
+			"base_url":         companyDBData.BaseUrl,
+		},
 	})
 }
 
@@ -240,24 +323,77 @@ func UpdateCompany(c echo.Context) error {
 	// Update fields if provided
 	updateMap := make(map[string]interface{})
 
-	if updateReq.BaseUrl != "" {
-		updateMap["base_url"] = updateReq.BaseUrl
-	}
-
+	careerSiteType := company.CareerSiteType
 	if updateReq.CareerSiteType != "" {
+		careerSiteType = updateReq.CareerSiteType
 		updateMap["career_site_type"] = updateReq.CareerSiteType
 	}
+	provider, known := scraper.Lookup(types.ScrapableWebsites(careerSiteType))
+	if !known && (updateReq.CareerSiteType != "" || updateReq.BaseUrl != "" || len(updateReq.ApiRequestBody) > 0) {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: fmt.Sprintf("Unknown career_site_type '%s', expected one of %v", careerSiteType, scraper.ProviderNames()),
+			Data:    nil,
+		})
+	}
+
+	// The base URL and body of a company moved to another provider were set up for the old one,
+	// the stored base URL is checked against the new provider and the body starts over
+	typeChanged := careerSiteType != company.CareerSiteType
+	baseURL := updateReq.BaseUrl
+	if typeChanged {
+		if baseURL == "" {
+			baseURL = company.BaseUrl
+		}
+		if len(updateReq.ApiRequestBody) == 0 {
+			updateMap["api_request_body"] = ""
+		}
+	}
+
+	if baseURL != "" {
+		updateMap["base_url"] = baseURL
+
+		// Providers that can be set up from a URL take any form of it, filters a Workday browser
+		// URL carries are left to api_request_body
+		if provider.NormaliseURL != nil {
+			source, err := provider.NormaliseURL(baseURL)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+					Message: fmt.Sprintf("Invalid %s base_url: %s", provider.Name, err.Error()),
+					Data:    nil,
+				})
+			}
+			updateMap["base_url"] = source.BaseUrl
+			if typeChanged && len(updateReq.ApiRequestBody) == 0 {
+				updateMap["api_request_body"] = source.ApiRequestBody
+			}
+		}
+	}
 
 	if len(updateReq.ApiRequestBody) > 0 {
-		// Compact the JSON if it's for Workday
-		var compactBuf bytes.Buffer
-		if err := json.Compact(&compactBuf, updateReq.ApiRequestBody); err != nil {
+		if provider.ValidateRequestBody == nil {
 			return c.JSON(http.StatusBadRequest, api_models.StdResponse{
-				Message: "Invalid JSON in api_request_body",
+				Message: fmt.Sprintf("%s companies don't use api_request_body", provider.Name),
 				Data:    nil,
 			})
 		}
-		updateMap["api_request_body"] = compactBuf.String()
+
+		source, err := provider.ValidateRequestBody(updateReq.ApiRequestBody)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+				Message: fmt.Sprintf("Invalid api_request_body: %s", err.Error()),
+				Data:    nil,
+			})
+		}
+		updateMap["api_request_body"] = source.ApiRequestBody
+		// A custom spec carries the base URL
+		if source.BaseUrl != "" {
+			updateMap["base_url"] = source.BaseUrl
+		}
+	} else if typeChanged && provider.ValidateRequestBody != nil && updateMap["api_request_body"] == "" {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: fmt.Sprintf("api_request_body is required to change career_site_type to %s", provider.Name),
+			Data:    nil,
+		})
 	}
 
 	if updateReq.ApiRequestQueryParam != "" {
@@ -266,6 +402,25 @@ func UpdateCompany(c echo.Context) error {
 
 	if updateReq.ToScrape != nil {
 		updateMap["to_scrape"] = *updateReq.ToScrape
+		// Turning scraping back on gives the company a clean health record
+		if *updateReq.ToScrape {
+			updateMap["unhealthy_since"] = nil
+			updateMap["consecutive_failures"] = 0
+		}
+	}
+
+	if updateReq.ScrapeIntervalMinutes != nil {
+		if *updateReq.ScrapeIntervalMinutes <= 0 {
+			return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+				Message: "scrape_interval_minutes must be greater than 0",
+				Data:    nil,
+			})
+		}
+		updateMap["scrape_interval_minutes"] = *updateReq.ScrapeIntervalMinutes
+	}
+
+	if updateReq.Priority != nil {
+		updateMap["priority"] = *updateReq.Priority
 	}
 
 	// Rename is handled separately since it's the primary key
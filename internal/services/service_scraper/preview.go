@@ -0,0 +1,279 @@
+package service_scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/scraper/common"
+	"job-scraper/internal/types"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPreviewLimit = 10
+	maxPreviewLimit     = 50
+	// A preview runs within the request, slow boards are cut short
+	previewTimeout = 60 * time.Second
+)
+
+// previewSink keeps the first jobs of a preview in memory instead of storing them, and stops
+// the scrape once it has enough
+type previewSink struct {
+	limit     int
+	startedAt time.Time
+	stop      context.CancelFunc
+
+	mu                sync.Mutex
+	jobs              []*db.Jobs
+	truncated         bool
+	pagesFetched      int
+	jobsListed        int
+	jobsFailed        int
+	warnings          []string
+	listingErr        error
+	listingFinishedAt time.Time
+	firstJobAt        time.Time
+}
+
+var _ common.ScrapeSink = (*previewSink)(nil)
+
+func (s *previewSink) CompanyStarted(company db.Companies) {}
+
+func (s *previewSink) PageFetched(company db.Companies, page int, jobsOnPage int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pagesFetched++
+}
+
+func (s *previewSink) JobQueued(company db.Companies, job *db.Jobs, detail any) {}
+
+func (s *previewSink) JobScraped(job *db.Jobs) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.jobs) >= s.limit {
+		return
+	}
+	if len(s.jobs) == 0 {
+		s.firstJobAt = time.Now()
+	}
+	s.jobs = append(s.jobs, job)
+
+	if len(s.jobs) == s.limit {
+		s.truncated = true
+		s.stop()
+	}
+}
+
+func (s *previewSink) JobFailed(job *db.Jobs, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Jobs abandoned once the limit is reached aren't failures
+	if s.truncated && errors.Is(err, context.Canceled) {
+		return
+	}
+	s.jobsFailed++
+	s.warnings = append(s.warnings, fmt.Sprintf("job %s failed: %s", job.JobLink, err))
+}
+
+func (s *previewSink) Warning(company db.Companies, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.warnings = append(s.warnings, message)
+}
+
+func (s *previewSink) CompanyListed(company db.Companies, jobsFound int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobsListed = jobsFound
+	s.listingFinishedAt = time.Now()
+	if err != nil && !(s.truncated && errors.Is(err, context.Canceled)) {
+		s.listingErr = err
+	}
+}
+
+func (s *previewSink) response(company db.Companies, scrapeDayLimit time.Time) api_models.CompanyPreviewResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response := api_models.CompanyPreviewResponse{
+		CareerSiteType: company.CareerSiteType,
+		BaseUrl:        company.BaseUrl,
+		ApiRequestBody: company.ApiRequestBody,
+		ScrapeSince:    formatScrapeSince(scrapeDayLimit),
+		Jobs:           make([]api_models.JobResponse, len(s.jobs)),
+		Truncated:      s.truncated,
+		PagesFetched:   s.pagesFetched,
+		JobsListed:     s.jobsListed,
+		JobsFailed:     s.jobsFailed,
+		Warnings:       s.warnings,
+		Timings: api_models.CompanyPreviewTimings{
+			ListingMs: s.listingFinishedAt.Sub(s.startedAt).Milliseconds(),
+			TotalMs:   time.Since(s.startedAt).Milliseconds(),
+		},
+	}
+	if response.Warnings == nil {
+		response.Warnings = []string{}
+	}
+	if s.listingErr != nil {
+		response.Error = s.listingErr.Error()
+	}
+	if !s.firstJobAt.IsZero() {
+		firstJobMs := s.firstJobAt.Sub(s.startedAt).Milliseconds()
+		response.Timings.FirstJobMs = &firstJobMs
+	}
+
+	for i, job := range s.jobs {
+		response.Jobs[i] = api_models.JobResponse{
+			JobHash:     job.JobHash,
+			JobId:       job.JobId,
+			JobRole:     job.JobRole,
+			JobDetails:  job.JobDetails,
+			JobPostDate: job.JobPostDate,
+			JobLink:     job.JobLink,
+			CompanyName: job.CompanyName,
+		}
+	}
+
+	return response
+}
+
+// previewSource builds the company a preview request describes, the same way adding it would
+func previewSource(previewReq api_models.PreviewCompanyRequest, payload []byte) (db.Companies, error) {
+	if previewReq.Provider == "auto" {
+		var autoCompData api_models.AddAutoDetectedCompanyScrapeList
+		if err := json.Unmarshal(payload, &autoCompData); err != nil {
+			return db.Companies{}, fmt.Errorf("invalid request body: %w", err)
+		}
+
+		detected, matched, err := detectATS(autoCompData.CareersUrl)
+		if err != nil {
+			return db.Companies{}, err
+		}
+		if !matched {
+			return db.Companies{}, fmt.Errorf("could not detect a supported ATS from careers_url")
+		}
+
+		return db.Companies{
+			Name:           previewReq.Name,
+			BaseUrl:        detected.BaseUrl,
+			CareerSiteType: string(detected.CareerSiteType),
+			ApiRequestBody: detected.ApiRequestBody,
+		}, nil
+	}
+
+	provider, ok := scraper.Lookup(types.ScrapableWebsites(previewReq.Provider))
+	if !ok {
+		return db.Companies{}, fmt.Errorf("unknown provider '%s', expected auto or one of %v", previewReq.Provider, scraper.ProviderNames())
+	}
+
+	source, err := provider.ValidatePayload(payload)
+	if err != nil {
+		return db.Companies{}, err
+	}
+
+	return db.Companies{
+		Name:           previewReq.Name,
+		BaseUrl:        source.BaseUrl,
+		CareerSiteType: string(provider.Name),
+		ApiRequestBody: source.ApiRequestBody,
+	}, nil
+}
+
+// PreviewCompany scrapes a company described by an add-company payload without saving anything,
+// and returns its first jobs with timings and warnings so the config can be checked first
+func PreviewCompany(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+	}
+
+	var previewReq api_models.PreviewCompanyRequest
+	if err := json.Unmarshal(payload, &previewReq); err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Invalid request body",
+			Data:    nil,
+		})
+	}
+
+	if previewReq.Limit <= 0 {
+		previewReq.Limit = defaultPreviewLimit
+	}
+	if previewReq.Limit > maxPreviewLimit {
+		previewReq.Limit = maxPreviewLimit
+	}
+	// A preview checks the config, so it takes every job unless a window is set, a board with no
+	// posting today would show nothing otherwise
+	if previewReq.ScrapeWindow == (api_models.ScrapeWindow{}) {
+		previewReq.Backfill = true
+	}
+	scrapeDayLimit, err := resolveScrapeWindow(previewReq.ScrapeWindow)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: fmt.Sprintf("Invalid scrape window: %s", err.Error()),
+			Data:    nil,
+		})
+	}
+	if previewReq.Name == "" {
+		previewReq.Name = "preview"
+	}
+
+	company, err := previewSource(previewReq, payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: fmt.Sprintf("Invalid company: %s", err.Error()),
+			Data:    nil,
+		})
+	}
+
+	jobScraper := scraper.JobScraperFactory(types.ScrapableWebsites(company.CareerSiteType))
+	if jobScraper == nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: fmt.Sprintf("No scraper registered for career_site_type '%s'", company.CareerSiteType),
+			Data:    nil,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), previewTimeout)
+	defer cancel()
+
+	sink := &previewSink{
+		limit:     previewReq.Limit,
+		startedAt: time.Now(),
+		stop:      cancel,
+	}
+
+	companiesToScrape := make(chan db.Companies, 1)
+	companiesToScrape <- company
+	close(companiesToScrape)
+
+	slog.Info("Previewing company", "career_site_type", company.CareerSiteType, "base_url", company.BaseUrl)
+	// A preview doesn't outlive the request, it skips the task queue
+	scraper.Scrape(ctx, jobScraper, companiesToScrape, scrapeDayLimit, sink)
+
+	response := sink.response(company, scrapeDayLimit)
+	message := fmt.Sprintf("Preview found %d jobs", len(response.Jobs))
+	if response.Error != "" {
+		message = "Preview listing failed"
+	}
+
+	return c.JSON(http.StatusOK, api_models.StdResponse{
+		Message: message,
+		Data:    response,
+	})
+}
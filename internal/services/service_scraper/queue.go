@@ -0,0 +1,271 @@
+package service_scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/scraper/common"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// A claimed task is invisible to other claims for this long, and extended while it runs
+	taskVisibilityTimeout = 5 * time.Minute
+	// Attempts of a task before it's dead-lettered
+	maxTaskAttempts = 3
+	// Delay before the first retry of a failed task, doubled on every attempt
+	taskRetryDelay = 30 * time.Second
+	// How often idle workers look for tasks while their provider still has some open
+	taskPollInterval = 2 * time.Second
+	// List tasks claimed at once, each company is listed on its own goroutine
+	listTaskBatch = 100
+)
+
+// taskRunner works the queued tasks of one provider's companies within a scrape run
+type taskRunner struct {
+	ctx            context.Context
+	run            *db.ScrapeRun
+	sink           *runSink
+	careerSiteType string
+	jobScraper     scraper.Scraper
+	// Oldest posting date to scrape of each company by name, the zero time for a backfill
+	scrapeDayLimits map[string]time.Time
+	// Closed to stop claiming tasks while letting the ones in flight finish, nil never is
+	stopClaiming <-chan struct{}
+}
+
+// work lists the provider's companies and fetches their jobs' details with scraper.DetailWorkers
+// workers, until the provider has no open task left or the run's context is done
+func (r *taskRunner) work() {
+	if !localWork {
+		r.waitForWorkers()
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(r.listCompanies)
+	for range scraper.DetailWorkers {
+		wg.Go(r.scrapeJobs)
+	}
+	wg.Wait()
+}
+
+// waitForWorkers waits for worker processes to work every task of the provider, or for the run's
+// context to be done
+func (r *taskRunner) waitForWorkers() {
+	for {
+		open, err := db.CountOpenScrapeTasks(r.run.ID, r.careerSiteType, "")
+		if err == nil && open == 0 {
+			return
+		}
+		if !r.wait() {
+			return
+		}
+	}
+}
+
+// claiming reports whether the runner may claim more tasks
+func (r *taskRunner) claiming() bool {
+	select {
+	case <-r.stopClaiming:
+		return false
+	default:
+		return r.ctx.Err() == nil
+	}
+}
+
+// wait pauses an idle worker until the poll interval passes or a job is queued, false once the
+// run's context is done or claiming stopped
+func (r *taskRunner) wait() bool {
+	timer := time.NewTimer(taskPollInterval)
+	defer timer.Stop()
+
+	select {
+	case <-r.ctx.Done():
+		return false
+	case <-r.stopClaiming:
+		return false
+	case <-r.sink.wake:
+		return true
+	case <-timer.C:
+		return true
+	}
+}
+
+func (r *taskRunner) listCompanies() {
+	var listers sync.WaitGroup
+	defer listers.Wait()
+
+	for r.claiming() {
+		tasks, err := db.ClaimScrapeTasks(r.ctx, r.run.ID, db.ScrapeTaskList, r.careerSiteType, listTaskBatch, taskVisibilityTimeout)
+		if err != nil && r.ctx.Err() == nil {
+			slog.Error("Failed to claim list tasks", "run", r.run.ID, "career_site_type", r.careerSiteType, "error", err)
+		}
+		for _, task := range tasks {
+			listers.Go(func() { r.listCompany(task) })
+		}
+		if len(tasks) > 0 {
+			continue
+		}
+
+		// Tasks still open are being listed, or waiting for a retry
+		open, err := db.CountOpenScrapeTasks(r.run.ID, r.careerSiteType, db.ScrapeTaskList)
+		if err == nil && open == 0 {
+			return
+		}
+		if !r.wait() {
+			return
+		}
+	}
+}
+
+func (r *taskRunner) scrapeJobs() {
+	for r.claiming() {
+		tasks, err := db.ClaimScrapeTasks(r.ctx, r.run.ID, db.ScrapeTaskDetail, r.careerSiteType, 1, taskVisibilityTimeout)
+		if err != nil && r.ctx.Err() == nil {
+			slog.Error("Failed to claim detail tasks", "run", r.run.ID, "career_site_type", r.careerSiteType, "error", err)
+		}
+		if len(tasks) > 0 {
+			r.scrapeJob(tasks[0])
+			continue
+		}
+
+		// Listing may still queue jobs, and failed jobs wait for their retry
+		open, err := db.CountOpenScrapeTasks(r.run.ID, r.careerSiteType, "")
+		if err == nil && open == 0 {
+			return
+		}
+		if !r.wait() {
+			return
+		}
+	}
+}
+
+func (r *taskRunner) listCompany(task db.ScrapeTask) {
+	var company db.Companies
+	if err := json.Unmarshal([]byte(task.Payload), &company); err != nil {
+		r.finishTask(task, fmt.Errorf("invalid list task: %w", err), func(err error) {
+			r.sink.CompanyListed(db.Companies{Name: task.CompanyName}, 0, err)
+		})
+		return
+	}
+
+	defer r.keepClaimed(task)()
+	r.sink.CompanyStarted(company)
+	jobsFound, err := r.jobScraper.ListJobs(r.ctx, company, r.scrapeDayLimits[company.Name], r.sink)
+	if err != nil {
+		slog.Error("Failed to list jobs", "company", company.Name, "attempt", task.Attempts, "error", err)
+	}
+
+	r.finishTask(task, err, func(err error) {
+		r.sink.CompanyListed(company, jobsFound, err)
+	})
+}
+
+func (r *taskRunner) scrapeJob(task db.ScrapeTask) {
+	var queued common.QueuedJob
+	if err := json.Unmarshal([]byte(task.Payload), &queued); err != nil || queued.Job == nil {
+		if err == nil {
+			err = errors.New("no job")
+		}
+		r.finishTask(task, fmt.Errorf("invalid detail task: %w", err), func(err error) {
+			r.sink.JobFailed(&db.Jobs{CompanyName: task.CompanyName}, err)
+		})
+		return
+	}
+
+	defer r.keepClaimed(task)()
+	err := r.jobScraper.ScrapeJob(r.ctx, queued, r.scrapeDayLimits[queued.Company.Name], r.sink)
+	if err != nil {
+		slog.Error("Failed to scrape job details",
+			"company", queued.Company.Name,
+			"jobLink", queued.Job.JobLink,
+			"attempt", task.Attempts,
+			"error", err)
+	}
+
+	r.finishTask(task, err, func(err error) {
+		r.sink.JobFailed(queued.Job, err)
+	})
+}
+
+// keepClaimed extends the claim of a task while it runs, so a long listing isn't claimed again.
+// The returned function stops it.
+func (r *taskRunner) keepClaimed(task db.ScrapeTask) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(taskVisibilityTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := db.ExtendScrapeTask(task.ID, taskVisibilityTimeout); err != nil {
+					slog.Error("Failed to extend scrape task claim", "task", task.ID, "error", err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// finishTask records the outcome of a task. A failed task is retried with a growing delay until
+// it's out of attempts, then dead-lettered. fail reports a task that won't be retried.
+func (r *taskRunner) finishTask(task db.ScrapeTask, taskErr error, fail func(error)) {
+	var err error
+	switch {
+	case taskErr == nil:
+		err = db.CompleteScrapeTask(task.ID)
+	case errors.Is(context.Cause(r.ctx), ErrShuttingDown):
+		// Picked up again when the run resumes
+		err = db.ReleaseScrapeTask(task.ID)
+	case r.ctx.Err() != nil:
+		// The run was cancelled, its other open tasks are closed once the workers stop. A task
+		// already closed was reported by whoever closed it.
+		var cancelled bool
+		cancelled, err = db.CancelScrapeTask(task.ID, r.ctx.Err().Error())
+		if cancelled {
+			fail(r.ctx.Err())
+		}
+	case task.Attempts >= maxTaskAttempts:
+		err = db.DeadLetterScrapeTask(task.ID, taskErr)
+		fail(taskErr)
+	default:
+		delay := taskRetryDelay << (task.Attempts - 1)
+		err = db.RetryScrapeTask(task.ID, taskErr, delay)
+		r.sink.Warning(db.Companies{Name: task.CompanyName},
+			fmt.Sprintf("%s task failed on attempt %d of %d, retrying in %s: %s", task.Kind, task.Attempts, maxTaskAttempts, delay, taskErr))
+	}
+	if err != nil {
+		slog.Error("Failed to record scrape task", "task", task.ID, "kind", task.Kind, "error", err)
+	}
+}
+
+// closeCancelledTasks reports the tasks a cancelled run left in the queue as cancelled, and
+// closes them
+func (r *taskRunner) closeCancelledTasks() {
+	tasks, err := db.CloseOpenScrapeTasks(r.run.ID, r.careerSiteType, db.ScrapeTaskCancelled, context.Canceled.Error())
+	if err != nil {
+		slog.Error("Failed to close cancelled scrape tasks", "run", r.run.ID, "career_site_type", r.careerSiteType, "error", err)
+		return
+	}
+
+	for _, task := range tasks {
+		switch task.Kind {
+		case db.ScrapeTaskList:
+			r.sink.CompanyListed(db.Companies{Name: task.CompanyName}, 0, context.Canceled)
+		case db.ScrapeTaskDetail:
+			var queued common.QueuedJob
+			if err := json.Unmarshal([]byte(task.Payload), &queued); err != nil || queued.Job == nil {
+				queued.Job = &db.Jobs{CompanyName: task.CompanyName}
+			}
+			r.sink.JobFailed(queued.Job, context.Canceled)
+		}
+	}
+}
@@ -0,0 +1,794 @@
+package service_scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/config"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/scraper/common"
+	"job-scraper/internal/types"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// ScrapeOptions selects what a scrape run covers
+type ScrapeOptions struct {
+	Trigger   string
+	Companies []db.Companies
+	// Oldest posting date to scrape, the zero time scrapes every job (backfill)
+	ScrapeDayLimit time.Time
+	// Oldest posting date to scrape of a company by name, in place of ScrapeDayLimit
+	CompanyScrapeDayLimits map[string]time.Time
+}
+
+// scrapeDayLimit returns the oldest posting date to scrape of a company
+func (opts ScrapeOptions) scrapeDayLimit(companyName string) time.Time {
+	if scrapeDayLimit, ok := opts.CompanyScrapeDayLimits[companyName]; ok {
+		return scrapeDayLimit
+	}
+	return opts.ScrapeDayLimit
+}
+
+// dueScrapeDayLimit returns the oldest posting date a scheduled scrape of a company covers: the
+// day of its last scrape, at most maxLookbackDays before today, or today for a company never
+// scraped. A company turned back on after months isn't backfilled.
+func dueScrapeDayLimit(lastScrapedAt *time.Time, today time.Time, maxLookbackDays int) time.Time {
+	if lastScrapedAt == nil {
+		return today
+	}
+	lastScraped := common.GetDateMidnight(*lastScrapedAt)
+	if lastScraped.After(today) {
+		return today
+	}
+	if oldest := today.AddDate(0, 0, -maxLookbackDays); maxLookbackDays > 0 && lastScraped.Before(oldest) {
+		return oldest
+	}
+	return lastScraped
+}
+
+// runScrapeDayLimits returns the oldest posting date to scrape of every company of a run by name,
+// the zero time for a backfill
+func runScrapeDayLimits(run *db.ScrapeRun) map[string]time.Time {
+	scrapeDayLimits := make(map[string]time.Time, len(run.Companies))
+	for _, runCompany := range run.Companies {
+		switch {
+		case runCompany.ScrapeSince != nil:
+			scrapeDayLimits[runCompany.CompanyName] = *runCompany.ScrapeSince
+		case run.ScrapeSince != nil:
+			// Recorded before companies had a window of their own
+			scrapeDayLimits[runCompany.CompanyName] = *run.ScrapeSince
+		default:
+			scrapeDayLimits[runCompany.CompanyName] = time.Time{}
+		}
+	}
+	return scrapeDayLimits
+}
+
+// resolveScrapeWindow turns a scrape window into the oldest posting date to scrape, the zero
+// time for a backfill
+func resolveScrapeWindow(window api_models.ScrapeWindow) (time.Time, error) {
+	optionsSet := 0
+	for _, set := range []bool{window.LookbackDays != 0, window.Since != "", window.Backfill} {
+		if set {
+			optionsSet++
+		}
+	}
+	if optionsSet > 1 {
+		return time.Time{}, fmt.Errorf("only one of lookback_days, since and backfill can be set")
+	}
+
+	today := common.GetTodayMidnight()
+	switch {
+	case window.Backfill:
+		return time.Time{}, nil
+	case window.Since != "":
+		since, err := time.ParseInLocation("2006-01-02", window.Since, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("since must be a date formatted YYYY-MM-DD")
+		}
+		if since.After(today) {
+			return time.Time{}, fmt.Errorf("since can't be in the future")
+		}
+		return since, nil
+	case window.LookbackDays < 0:
+		return time.Time{}, fmt.Errorf("lookback_days can't be negative")
+	default:
+		return today.AddDate(0, 0, -window.LookbackDays), nil
+	}
+}
+
+// formatScrapeSince formats the oldest posting date of a scrape, nil for a backfill
+func formatScrapeSince(scrapeDayLimit time.Time) *string {
+	if scrapeDayLimit.IsZero() {
+		return nil
+	}
+	since := scrapeDayLimit.Format("2006-01-02")
+	return &since
+}
+
+// activeRuns holds the cancel function of every scrape run in progress or worked in this process.
+// running counts the runs from the moment they start until they're recorded or interrupted, so
+// shutdown can wait on them. draining is closed on shutdown to stop workers claiming tasks.
+var activeRuns = struct {
+	sync.Mutex
+	cancels      map[uint]context.CancelCauseFunc
+	running      sync.WaitGroup
+	shuttingDown bool
+	draining     chan struct{}
+}{cancels: make(map[uint]context.CancelCauseFunc), draining: make(chan struct{})}
+
+// cancelScrapeRun cancels a run in progress, false if the run isn't running here
+func cancelScrapeRun(runID uint) bool {
+	activeRuns.Lock()
+	defer activeRuns.Unlock()
+
+	cancel, ok := activeRuns.cancels[runID]
+	if ok {
+		cancel(nil)
+	}
+	return ok
+}
+
+// Advisory lock held for the whole of a scrape run, by whichever replica runs it
+const scrapeRunLockName = "job_scraper_scrape_run"
+
+// ErrScrapeRunInProgress is returned when another scrape run, possibly on another replica, holds
+// the scrape lock
+var ErrScrapeRunInProgress = errors.New("a scrape run is already in progress")
+
+// ErrShuttingDown is returned for scrape runs started once the server is shutting down
+var ErrShuttingDown = errors.New("the server is shutting down")
+
+// InterruptedRunError is returned for scrape runs started while a run left unfinished by a stopped
+// process waits to resume, no new run is started. It matches ErrScrapeRunInProgress.
+type InterruptedRunError struct {
+	RunID uint
+}
+
+func (e *InterruptedRunError) Error() string {
+	return fmt.Sprintf("scrape run %d was interrupted and waits to resume, no new run was started", e.RunID)
+}
+
+func (e *InterruptedRunError) Unwrap() error {
+	return ErrScrapeRunInProgress
+}
+
+// How often API processes look for an interrupted run to resume
+const interruptedRunPollInterval = time.Minute
+
+// Time given to interrupted runs to put their tasks back in the queue
+const shutdownCancelGrace = 15 * time.Second
+
+// Shutdown refuses new scrape runs and waits for the runs in progress to finish, a worker stops
+// claiming tasks and waits for the ones in flight instead. Runs still going when ctx is done are
+// interrupted, their open tasks stay queued for a worker or for the run to resume on the next
+// start.
+func Shutdown(ctx context.Context) error {
+	activeRuns.Lock()
+	if !activeRuns.shuttingDown {
+		activeRuns.shuttingDown = true
+		close(activeRuns.draining)
+	}
+	activeRuns.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		activeRuns.running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+	}
+
+	activeRuns.Lock()
+	slog.Warn("Shutdown deadline reached, interrupting scrape runs in progress", "runs", len(activeRuns.cancels))
+	for _, cancel := range activeRuns.cancels {
+		cancel(ErrShuttingDown)
+	}
+	activeRuns.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(shutdownCancelGrace):
+		return errors.New("scrape runs didn't stop after being interrupted")
+	}
+}
+
+// activeScrapeRun returns the newest run still marked as running
+func activeScrapeRun() (*db.ScrapeRun, error) {
+	var run db.ScrapeRun
+	if err := db.DB.Where("status = ?", db.ScrapeRunRunning).Order("id DESC").First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// interruptedRun returns the newest run left running by a process that stopped mid-run, with its
+// companies, when it still has queued tasks to resume from. It's nil otherwise.
+func interruptedRun() (*db.ScrapeRun, error) {
+	var run db.ScrapeRun
+	err := db.DB.Preload("Companies").Where("status = ?", db.ScrapeRunRunning).Order("id DESC").First(&run).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks int64
+	if err := db.DB.Model(&db.ScrapeTask{}).Where("scrape_run_id = ?", run.ID).Count(&tasks).Error; err != nil {
+		return nil, err
+	}
+	if tasks == 0 {
+		return nil, nil
+	}
+	return &run, nil
+}
+
+// failInterruptedRuns closes the runs left running by a process that stopped mid-run, except the
+// one being resumed. They can't be running anymore once the scrape lock is free.
+func failInterruptedRuns(resumedRunID uint) {
+	now := time.Now()
+	const interrupted = "scrape run was interrupted"
+
+	result := db.DB.Model(&db.ScrapeRun{}).
+		Where("status = ? AND id <> ?", db.ScrapeRunRunning, resumedRunID).
+		Updates(map[string]interface{}{"status": db.ScrapeRunFailed, "finished_at": now})
+	if result.Error != nil {
+		slog.Error("Failed to close interrupted scrape runs", "error", result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		return
+	}
+
+	if err := db.DB.Model(&db.ScrapeRunCompany{}).
+		Where("status = ? AND scrape_run_id <> ?", db.ScrapeRunRunning, resumedRunID).
+		Updates(map[string]interface{}{"status": db.ScrapeRunFailed, "finished_at": now, "error": interrupted}).Error; err != nil {
+		slog.Error("Failed to close interrupted scrape run companies", "error", err)
+	}
+	slog.Warn("Closed interrupted scrape runs", "runs", result.RowsAffected)
+}
+
+// lockScrapeRun takes the scrape lock for a run in this process. The returned function releases
+// it once the run is recorded or interrupted.
+func lockScrapeRun() (release func(), err error) {
+	activeRuns.Lock()
+	if activeRuns.shuttingDown {
+		activeRuns.Unlock()
+		return nil, ErrShuttingDown
+	}
+	activeRuns.running.Add(1)
+	activeRuns.Unlock()
+
+	lock, locked, err := db.TryAdvisoryLock(context.Background(), scrapeRunLockName)
+	if err != nil {
+		activeRuns.running.Done()
+		return nil, err
+	}
+	if !locked {
+		activeRuns.running.Done()
+		return nil, ErrScrapeRunInProgress
+	}
+
+	return func() {
+		if err := lock.Release(); err != nil {
+			slog.Error("Failed to release scrape run lock", "error", err)
+		}
+		activeRuns.running.Done()
+	}, nil
+}
+
+// resumeInterruptedRun takes over the run a stopped process left with queued tasks, if any, and
+// fails the other runs it left running. It reports whether a run was resumed, the lock is only
+// kept in that case.
+func resumeInterruptedRun(releaseLock func()) bool {
+	run, err := interruptedRun()
+	if err != nil {
+		slog.Error("Failed to fetch interrupted scrape run", "error", err)
+	}
+	if run == nil {
+		failInterruptedRuns(0)
+		return false
+	}
+	failInterruptedRuns(run.ID)
+
+	// Tasks claimed by a process that crashed are claimed again once their claim runs out, the
+	// ones claimed by live workers stay theirs
+	slog.Warn("Resuming interrupted scrape run", "run", run.ID, "trigger", run.Trigger)
+	processRun(run, releaseLock)
+	return true
+}
+
+// ResumeInterruptedRun resumes the scrape run a stopped process left unfinished, if there is one
+// and no other replica is running scrapes
+func ResumeInterruptedRun() {
+	releaseLock, err := lockScrapeRun()
+	if err != nil {
+		if !errors.Is(err, ErrScrapeRunInProgress) && !errors.Is(err, ErrShuttingDown) {
+			slog.Error("Failed to take scrape run lock", "error", err)
+		}
+		return
+	}
+	if !resumeInterruptedRun(releaseLock) {
+		releaseLock()
+	}
+}
+
+// ResumeInterruptedRuns looks for an interrupted run to resume every interruptedRunPollInterval,
+// like one left by a replica that stopped while this one was running scrapes, until Shutdown
+func ResumeInterruptedRuns() {
+	ticker := time.NewTicker(interruptedRunPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-activeRuns.draining:
+			return
+		case <-ticker.C:
+			ResumeInterruptedRun()
+		}
+	}
+}
+
+// StartScrapeRun records a scrape run for the companies, queues a list task for each of them and
+// works the tasks in the background. Only one run goes at a time across every replica,
+// ErrScrapeRunInProgress is returned otherwise, and an InterruptedRunError while a run left
+// unfinished by a stopped process waits to resume. The returned channel is closed once the run is
+// finished and recorded, or interrupted by shutdown.
+func StartScrapeRun(opts ScrapeOptions) (*db.ScrapeRun, <-chan struct{}, error) {
+	releaseLock, err := lockScrapeRun()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	interrupted, err := interruptedRun()
+	if err != nil {
+		releaseLock()
+		return nil, nil, fmt.Errorf("failed to fetch interrupted scrape run: %w", err)
+	}
+	if interrupted != nil {
+		releaseLock()
+		return nil, nil, &InterruptedRunError{RunID: interrupted.ID}
+	}
+	failInterruptedRuns(0)
+
+	run := &db.ScrapeRun{
+		Status:         db.ScrapeRunRunning,
+		Trigger:        opts.Trigger,
+		StartedAt:      time.Now(),
+		CompaniesTotal: len(opts.Companies),
+		Companies:      make([]db.ScrapeRunCompany, len(opts.Companies)),
+	}
+	// The run records the oldest posting date of its companies, nil once one is a backfill
+	var oldest time.Time
+	backfill := false
+	for i, company := range opts.Companies {
+		run.Companies[i] = db.ScrapeRunCompany{
+			CompanyName:    company.Name,
+			CareerSiteType: company.CareerSiteType,
+			Status:         db.ScrapeRunRunning,
+		}
+		scrapeDayLimit := opts.scrapeDayLimit(company.Name)
+		if scrapeDayLimit.IsZero() {
+			backfill = true
+			continue
+		}
+		run.Companies[i].ScrapeSince = &scrapeDayLimit
+		if oldest.IsZero() || scrapeDayLimit.Before(oldest) {
+			oldest = scrapeDayLimit
+		}
+	}
+	if !backfill && !oldest.IsZero() {
+		run.ScrapeSince = &oldest
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+
+		tasks := make([]db.ScrapeTask, len(opts.Companies))
+		for i, company := range opts.Companies {
+			payload, err := json.Marshal(company)
+			if err != nil {
+				return err
+			}
+			tasks[i] = db.ScrapeTask{
+				ScrapeRunID:    run.ID,
+				Kind:           db.ScrapeTaskList,
+				CareerSiteType: company.CareerSiteType,
+				Status:         db.ScrapeTaskPending,
+				Key:            db.ScrapeTaskList + ":" + company.Name,
+				CompanyName:    company.Name,
+				Payload:        string(payload),
+			}
+		}
+		return tx.CreateInBatches(&tasks, 500).Error
+	})
+	if err != nil {
+		releaseLock()
+		return nil, nil, fmt.Errorf("failed to record scrape run: %w", err)
+	}
+
+	return run, processRun(run, releaseLock), nil
+}
+
+// processRun works the queued tasks of a run in the background, every provider at once, and
+// records the run once no task is left. A run interrupted by shutdown is left running with its
+// open tasks queued. releaseLock is called once the run is recorded or interrupted, and the
+// returned channel closed.
+func processRun(run *db.ScrapeRun, releaseLock func()) <-chan struct{} {
+	scrapeDayLimits := runScrapeDayLimits(run)
+
+	// Companies keep their order within a provider, providers start in order of their first company
+	companiesByType := make(map[types.ScrapableWebsites][]db.Companies)
+	var careerSiteTypes []types.ScrapableWebsites
+	for _, runCompany := range run.Companies {
+		careerSiteType := types.ScrapableWebsites(runCompany.CareerSiteType)
+		if _, ok := companiesByType[careerSiteType]; !ok {
+			careerSiteTypes = append(careerSiteTypes, careerSiteType)
+		}
+		companiesByType[careerSiteType] = append(companiesByType[careerSiteType], db.Companies{
+			Name:           runCompany.CompanyName,
+			CareerSiteType: runCompany.CareerSiteType,
+		})
+	}
+
+	// The run outlives the request, so it doesn't use the request context
+	ctx, cancel := context.WithCancelCause(context.Background())
+	activeRuns.Lock()
+	activeRuns.cancels[run.ID] = cancel
+	activeRuns.Unlock()
+
+	scrapeRunEvents.open(run.ID)
+	sink := newRunSink(run)
+
+	var wg sync.WaitGroup
+	for _, careerSiteType := range careerSiteTypes {
+		typeCompanies := companiesByType[careerSiteType]
+		jobScraper := scraper.JobScraperFactory(careerSiteType)
+		if jobScraper == nil {
+			slog.Debug("This Scraper Logic doesn't exist yet", "career_site_type", careerSiteType)
+			failure := fmt.Sprintf("no scraper registered for career_site_type %q", careerSiteType)
+			if _, err := db.CloseOpenScrapeTasks(run.ID, string(careerSiteType), db.ScrapeTaskDead, failure); err != nil {
+				slog.Error("Failed to close scrape tasks", "run", run.ID, "career_site_type", careerSiteType, "error", err)
+			}
+			sink.finishCompanies(typeCompanies, failure)
+			continue
+		}
+
+		runner := &taskRunner{
+			ctx:             ctx,
+			run:             run,
+			sink:            sink,
+			careerSiteType:  string(careerSiteType),
+			jobScraper:      jobScraper,
+			scrapeDayLimits: scrapeDayLimits,
+		}
+		wg.Go(func() {
+			runner.work()
+			if errors.Is(context.Cause(ctx), ErrShuttingDown) {
+				return
+			}
+			if ctx.Err() != nil {
+				runner.closeCancelledTasks()
+			}
+			sink.finishCompanies(typeCompanies, "")
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wg.Wait()
+
+		activeRuns.Lock()
+		delete(activeRuns.cancels, run.ID)
+		activeRuns.Unlock()
+		// The context is only cancelled before this point by a cancel request or shutdown
+		interrupted := errors.Is(context.Cause(ctx), ErrShuttingDown)
+		cancelled := ctx.Err() != nil
+		cancel(nil)
+
+		if interrupted {
+			slog.Warn("Scrape run interrupted, it resumes on the next start", "run", run.ID)
+			scrapeRunEvents.close(run.ID, nil)
+		} else {
+			sink.finishRun(cancelled)
+		}
+		releaseLock()
+	}()
+
+	return done
+}
+
+// ScrapeEnabledCompanies starts a scrape run over every company enabled for scraping, the run is
+// nil when no company is enabled
+func ScrapeEnabledCompanies(trigger string, scrapeDayLimit time.Time) (*db.ScrapeRun, <-chan struct{}, error) {
+	var companies []db.Companies
+	if err := db.DB.Where(&db.Companies{ToScrape: true}).Order("priority DESC, name").Find(&companies).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch companies: %w", err)
+	}
+
+	slog.Info("Starting job scraping session", "total_companies_enabled", len(companies), "trigger", trigger)
+	if len(companies) == 0 {
+		slog.Warn("No companies enabled for scraping")
+		return nil, nil, nil
+	}
+
+	return StartScrapeRun(ScrapeOptions{
+		Trigger:        trigger,
+		Companies:      companies,
+		ScrapeDayLimit: scrapeDayLimit,
+	})
+}
+
+// Slack given to scrape intervals, so a company scraped by one tick is due again on the tick an
+// interval later even though that tick comes a moment early relative to the recorded scrape
+const scrapeDueGrace = 5 * time.Minute
+
+// ScrapeDueCompanies starts a scrape run over the enabled companies whose scrape interval has
+// passed since their last scrape, highest priority first. The run is nil when no company is due.
+func ScrapeDueCompanies(trigger string) (*db.ScrapeRun, <-chan struct{}, error) {
+	var companies []db.Companies
+	err := db.DB.Where(&db.Companies{ToScrape: true}).
+		Where("last_scraped_at IS NULL OR last_scraped_at + scrape_interval_minutes * interval '1 minute' <= ?", time.Now().Add(scrapeDueGrace)).
+		Order("priority DESC, last_scraped_at ASC NULLS FIRST").
+		Find(&companies).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch due companies: %w", err)
+	}
+
+	slog.Info("Starting job scraping session", "companies_due", len(companies), "trigger", trigger)
+	if len(companies) == 0 {
+		return nil, nil, nil
+	}
+
+	// Each company is scraped from the day it was last scraped, so a company on a long interval
+	// doesn't miss the jobs posted in between
+	today := common.GetTodayMidnight()
+	maxLookbackDays := config.GetSecrets().ScheduledLookbackMaxDays
+	scrapeDayLimits := make(map[string]time.Time, len(companies))
+	for _, company := range companies {
+		scrapeDayLimits[company.Name] = dueScrapeDayLimit(company.LastScrapedAt, today, maxLookbackDays)
+	}
+
+	return StartScrapeRun(ScrapeOptions{
+		Trigger:                trigger,
+		Companies:              companies,
+		ScrapeDayLimit:         today,
+		CompanyScrapeDayLimits: scrapeDayLimits,
+	})
+}
+
+func toScrapeRunResponse(run db.ScrapeRun) api_models.ScrapeRunResponse {
+	response := api_models.ScrapeRunResponse{
+		ID:              run.ID,
+		Status:          run.Status,
+		Trigger:         run.Trigger,
+		StartedAt:       run.StartedAt.Format(time.RFC3339),
+		CompaniesTotal:  run.CompaniesTotal,
+		CompaniesFailed: run.CompaniesFailed,
+		JobsListed:      run.JobsListed,
+		JobsInserted:    run.JobsInserted,
+		JobsDuplicate:   run.JobsDuplicate,
+		JobsFailed:      run.JobsFailed,
+	}
+	if run.FinishedAt != nil {
+		finishedAt := run.FinishedAt.Format(time.RFC3339)
+		response.FinishedAt = &finishedAt
+	}
+	if run.ScrapeSince != nil {
+		response.ScrapeSince = formatScrapeSince(*run.ScrapeSince)
+	}
+
+	for _, runCompany := range run.Companies {
+		companyResponse := api_models.ScrapeRunCompanyResponse{
+			CompanyName:    runCompany.CompanyName,
+			CareerSiteType: runCompany.CareerSiteType,
+			Status:         runCompany.Status,
+			JobsListed:     runCompany.JobsListed,
+			JobsInserted:   runCompany.JobsInserted,
+			JobsDuplicate:  runCompany.JobsDuplicate,
+			JobsFailed:     runCompany.JobsFailed,
+			JobsSeen:       runCompany.JobsSeen,
+			Error:          runCompany.Error,
+		}
+		if runCompany.FinishedAt != nil {
+			finishedAt := runCompany.FinishedAt.Format(time.RFC3339)
+			companyResponse.FinishedAt = &finishedAt
+		}
+		if runCompany.ScrapeSince != nil {
+			companyResponse.ScrapeSince = formatScrapeSince(*runCompany.ScrapeSince)
+		}
+		response.Companies = append(response.Companies, companyResponse)
+	}
+
+	return response
+}
+
+// GetScrapeRuns lists scrape runs, newest first, without their per-company results
+func GetScrapeRuns(c echo.Context) error {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	var totalCount int64
+	if err := db.DB.Model(&db.ScrapeRun{}).Count(&totalCount).Error; err != nil {
+		slog.Error("Failed to count scrape runs", "error", err)
+		return c.JSON(http.StatusInternalServerError, api_models.StdResponse{
+			Message: "Failed to fetch scrape runs",
+			Data:    nil,
+		})
+	}
+
+	var runs []db.ScrapeRun
+	if err := db.DB.Order("id DESC").Limit(limit).Offset(offset).Find(&runs).Error; err != nil {
+		slog.Error("Failed to fetch scrape runs", "error", err)
+		return c.JSON(http.StatusInternalServerError, api_models.StdResponse{
+			Message: "Failed to fetch scrape runs",
+			Data:    nil,
+		})
+	}
+
+	runResponses := make([]api_models.ScrapeRunResponse, len(runs))
+	for i, run := range runs {
+		runResponses[i] = toScrapeRunResponse(run)
+	}
+
+	return c.JSON(http.StatusOK, api_models.StdResponse{
+		Message: "Scrape runs retrieved successfully",
+		Data: api_models.ScrapeRunListResponse{
+			Runs:    runResponses,
+			Total:   totalCount,
+			Limit:   limit,
+			Offset:  offset,
+			HasMore: int64(offset+limit) < totalCount,
+		},
+	})
+}
+
+// GetScrapeRun returns one scrape run with the result of every company
+func GetScrapeRun(c echo.Context) error {
+	runID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Invalid scrape run id",
+			Data:    nil,
+		})
+	}
+
+	var run db.ScrapeRun
+	err = db.DB.Preload("Companies", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("company_name")
+	}).First(&run, runID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.JSON(http.StatusNotFound, api_models.StdResponse{
+				Message: fmt.Sprintf("Scrape run %d not found", runID),
+				Data:    nil,
+			})
+		}
+		slog.Error("Failed to fetch scrape run", "error", err, "run", runID)
+		return c.JSON(http.StatusInternalServerError, api_models.StdResponse{
+			Message: "Failed to fetch scrape run",
+			Data:    nil,
+		})
+	}
+
+	return c.JSON(http.StatusOK, api_models.StdResponse{
+		Message: "Scrape run retrieved successfully",
+		Data:    toScrapeRunResponse(run),
+	})
+}
+
+// shuttingDownResponse answers a scrape request refused because the server is shutting down
+func shuttingDownResponse(c echo.Context) error {
+	return c.JSON(http.StatusServiceUnavailable, api_models.StdResponse{
+		Message: ErrShuttingDown.Error(),
+		Data:    nil,
+	})
+}
+
+// interruptedRunResponse answers a scrape request refused because an interrupted run waits to
+// resume, with the details of that run
+func interruptedRunResponse(c echo.Context, interrupted *InterruptedRunError) error {
+	var data interface{}
+	var run db.ScrapeRun
+	if err := db.DB.First(&run, interrupted.RunID).Error; err == nil {
+		data = toScrapeRunResponse(run)
+	} else {
+		slog.Error("Failed to fetch interrupted scrape run", "error", err, "run", interrupted.RunID)
+	}
+
+	return c.JSON(http.StatusConflict, api_models.StdResponse{
+		Message: interrupted.Error(),
+		Data:    data,
+	})
+}
+
+// scrapeRunInProgressResponse answers a scrape request refused because another run holds the
+// scrape lock, with the details of that run
+func scrapeRunInProgressResponse(c echo.Context) error {
+	var data interface{}
+	if run, err := activeScrapeRun(); err == nil {
+		data = toScrapeRunResponse(*run)
+	} else if err != gorm.ErrRecordNotFound {
+		slog.Error("Failed to fetch active scrape run", "error", err)
+	}
+
+	return c.JSON(http.StatusConflict, api_models.StdResponse{
+		Message: ErrScrapeRunInProgress.Error(),
+		Data:    data,
+	})
+}
+
+// CancelScrapeRun stops a scrape run in progress. Listing stops at the next page, queued jobs are
+// abandoned and reported as failed, and the run is recorded as cancelled once its workers are done.
+func CancelScrapeRun(c echo.Context) error {
+	runID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, api_models.StdResponse{
+			Message: "Invalid scrape run id",
+			Data:    nil,
+		})
+	}
+
+	if cancelScrapeRun(uint(runID)) {
+		slog.Info("Cancelling scrape run", "run", runID)
+		return c.JSON(http.StatusAccepted, api_models.StdResponse{
+			Message: fmt.Sprintf("Cancelling scrape run %d", runID),
+			Data: map[string]interface{}{
+				"run_id": runID,
+			},
+		})
+	}
+
+	var run db.ScrapeRun
+	if err := db.DB.First(&run, runID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return c.JSON(http.StatusNotFound, api_models.StdResponse{
+				Message: fmt.Sprintf("Scrape run %d not found", runID),
+				Data:    nil,
+			})
+		}
+		slog.Error("Failed to fetch scrape run", "error", err, "run", runID)
+		return c.JSON(http.StatusInternalServerError, api_models.StdResponse{
+			Message: "Failed to fetch scrape run",
+			Data:    nil,
+		})
+	}
+
+	message := fmt.Sprintf("Scrape run %d is not running", runID)
+	if run.Status == db.ScrapeRunRunning {
+		// Runs can only be cancelled by the replica running them
+		message = fmt.Sprintf("Scrape run %d is not running on this instance", runID)
+	}
+	return c.JSON(http.StatusConflict, api_models.StdResponse{
+		Message: message,
+		Data: map[string]interface{}{
+			"status": run.Status,
+		},
+	})
+}
@@ -0,0 +1,199 @@
+package service_scraper
+
+import (
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper/common"
+	"testing"
+	"time"
+)
+
+func TestResolveScrapeWindow(t *testing.T) {
+	today := common.GetTodayMidnight()
+
+	tests := []struct {
+		name        string
+		window      api_models.ScrapeWindow
+		expectError bool
+		expected    time.Time
+	}{
+		{
+			name:     "Default scrapes today",
+			window:   api_models.ScrapeWindow{},
+			expected: today,
+		},
+		{
+			name:     "Lookback days",
+			window:   api_models.ScrapeWindow{LookbackDays: 3},
+			expected: today.AddDate(0, 0, -3),
+		},
+		{
+			name:     "Since date",
+			window:   api_models.ScrapeWindow{Since: "2025-01-15"},
+			expected: time.Date(2025, 1, 15, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "Since today",
+			window:   api_models.ScrapeWindow{Since: today.Format("2006-01-02")},
+			expected: today,
+		},
+		{
+			name:     "Backfill scrapes every job",
+			window:   api_models.ScrapeWindow{Backfill: true},
+			expected: time.Time{},
+		},
+		{
+			name:        "Negative lookback",
+			window:      api_models.ScrapeWindow{LookbackDays: -1},
+			expectError: true,
+		},
+		{
+			name:        "Since in another format",
+			window:      api_models.ScrapeWindow{Since: "15/01/2025"},
+			expectError: true,
+		},
+		{
+			name:        "Since with a time",
+			window:      api_models.ScrapeWindow{Since: "2025-01-15T10:00:00Z"},
+			expectError: true,
+		},
+		{
+			name:        "Since in the future",
+			window:      api_models.ScrapeWindow{Since: today.AddDate(0, 0, 2).Format("2006-01-02")},
+			expectError: true,
+		},
+		{
+			name:        "Lookback and since",
+			window:      api_models.ScrapeWindow{LookbackDays: 3, Since: "2025-01-15"},
+			expectError: true,
+		},
+		{
+			name:        "Lookback and backfill",
+			window:      api_models.ScrapeWindow{LookbackDays: 3, Backfill: true},
+			expectError: true,
+		},
+		{
+			name:        "Since and backfill",
+			window:      api_models.ScrapeWindow{Since: "2025-01-15", Backfill: true},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolveScrapeWindow(tt.window)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none, result %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDueScrapeDayLimit(t *testing.T) {
+	today := time.Date(2025, 1, 15, 0, 0, 0, 0, time.Local)
+	lastScraped := func(at time.Time) *time.Time { return &at }
+
+	tests := []struct {
+		name            string
+		lastScrapedAt   *time.Time
+		maxLookbackDays int
+		expected        time.Time
+	}{
+		{
+			name:          "Never scraped",
+			lastScrapedAt: nil,
+			expected:      today,
+		},
+		{
+			name:          "Scraped earlier today",
+			lastScrapedAt: lastScraped(today.Add(6 * time.Hour)),
+			expected:      today,
+		},
+		{
+			name:          "Scraped days ago",
+			lastScrapedAt: lastScraped(time.Date(2025, 1, 8, 18, 30, 0, 0, time.Local)),
+			expected:      time.Date(2025, 1, 8, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:          "Scraped after today",
+			lastScrapedAt: lastScraped(today.AddDate(0, 0, 1)),
+			expected:      today,
+		},
+		{
+			name:            "Scraped within the lookback cap",
+			lastScrapedAt:   lastScraped(time.Date(2025, 1, 8, 18, 30, 0, 0, time.Local)),
+			maxLookbackDays: 14,
+			expected:        time.Date(2025, 1, 8, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:            "Scraped before the lookback cap",
+			lastScrapedAt:   lastScraped(time.Date(2024, 6, 1, 9, 0, 0, 0, time.Local)),
+			maxLookbackDays: 14,
+			expected:        today.AddDate(0, 0, -14),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := dueScrapeDayLimit(tt.lastScrapedAt, today, tt.maxLookbackDays)
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRunScrapeDayLimits(t *testing.T) {
+	runSince := time.Date(2025, 1, 10, 0, 0, 0, 0, time.Local)
+	companySince := time.Date(2025, 1, 12, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		run      db.ScrapeRun
+		expected time.Time
+	}{
+		{
+			name: "Company window",
+			run: db.ScrapeRun{ScrapeSince: &runSince, Companies: []db.ScrapeRunCompany{
+				{CompanyName: "Netflix", ScrapeSince: &companySince},
+			}},
+			expected: companySince,
+		},
+		{
+			name: "Run window of a run recorded without company windows",
+			run: db.ScrapeRun{ScrapeSince: &runSince, Companies: []db.ScrapeRunCompany{
+				{CompanyName: "Netflix"},
+			}},
+			expected: runSince,
+		},
+		{
+			name: "Backfill",
+			run: db.ScrapeRun{Companies: []db.ScrapeRunCompany{
+				{CompanyName: "Netflix"},
+			}},
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := runScrapeDayLimits(&tt.run)["Netflix"]
+			if !ok {
+				t.Fatalf("Expected a scrape day limit for Netflix, got %v", result)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package service_scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper/common"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// runSink stores scraped jobs, queues listed jobs as detail tasks and records the outcome of every
+// company of a scrape run. Counts are kept in the database, so a resumed run carries on from them.
+type runSink struct {
+	run *db.ScrapeRun
+	// Set in worker processes, their events are relayed to the process streaming the run
+	remote bool
+	// Nudges idle detail workers when a job is queued
+	wake chan struct{}
+	// Company rows of the run keyed by company name, read only once the sink is built
+	companies map[string]db.ScrapeRunCompany
+}
+
+var _ common.ScrapeSink = (*runSink)(nil)
+
+// newRunSink tracks the company rows of a run, keyed by company name
+func newRunSink(run *db.ScrapeRun) *runSink {
+	sink := &runSink{
+		run:       run,
+		wake:      make(chan struct{}, 1),
+		companies: make(map[string]db.ScrapeRunCompany, len(run.Companies)),
+	}
+	for _, runCompany := range run.Companies {
+		sink.companies[runCompany.CompanyName] = runCompany
+	}
+	return sink
+}
+
+// publish streams a progress event of the run to its subscribers
+func (s *runSink) publish(event api_models.ScrapeRunEvent) {
+	event.RunID = s.run.ID
+	event.Time = time.Now().Format(time.RFC3339)
+	if s.remote {
+		relayRunEvent(event)
+		return
+	}
+	scrapeRunEvents.publish(event)
+}
+
+// countJob adds a job to one of the counts of its company, a failed job also records its error
+func (s *runSink) countJob(runCompany db.ScrapeRunCompany, column string, jobErr error) {
+	updates := map[string]interface{}{column: gorm.Expr(column + " + 1")}
+	if jobErr != nil {
+		updates["error"] = jobErr.Error()
+	}
+	if err := db.DB.Model(&db.ScrapeRunCompany{ID: runCompany.ID}).UpdateColumns(updates).Error; err != nil {
+		slog.Error("Failed to count scrape run job",
+			"run", s.run.ID,
+			"company", runCompany.CompanyName,
+			"count", column,
+			"error", err)
+	}
+}
+
+func (s *runSink) CompanyStarted(company db.Companies) {
+	// A retried listing counts the jobs on its pages afresh
+	if runCompany, ok := s.companies[company.Name]; ok {
+		if err := db.DB.Model(&db.ScrapeRunCompany{ID: runCompany.ID}).UpdateColumn("jobs_seen", 0).Error; err != nil {
+			slog.Error("Failed to reset scrape run company jobs seen", "run", s.run.ID, "company", company.Name, "error", err)
+		}
+	}
+
+	s.publish(api_models.ScrapeRunEvent{
+		Type:        EventCompanyStarted,
+		CompanyName: company.Name,
+	})
+}
+
+func (s *runSink) PageFetched(company db.Companies, page int, jobsOnPage int) {
+	if runCompany, ok := s.companies[company.Name]; ok && jobsOnPage > 0 {
+		if err := db.DB.Model(&db.ScrapeRunCompany{ID: runCompany.ID}).
+			UpdateColumn("jobs_seen", gorm.Expr("jobs_seen + ?", jobsOnPage)).Error; err != nil {
+			slog.Error("Failed to count scrape run company jobs seen", "run", s.run.ID, "company", company.Name, "error", err)
+		}
+	}
+
+	s.publish(api_models.ScrapeRunEvent{
+		Type:        EventPageFetched,
+		CompanyName: company.Name,
+		Page:        page,
+		Jobs:        &jobsOnPage,
+	})
+}
+
+// JobQueued stores the job as a detail task for the run's detail workers
+func (s *runSink) JobQueued(company db.Companies, job *db.Jobs, detail any) {
+	queued, err := common.NewQueuedJob(company, job, detail)
+	if err == nil {
+		err = s.enqueueDetail(queued)
+	}
+	if err != nil {
+		slog.Error("Failed to queue job for its details", "run", s.run.ID, "company", company.Name, "error", err)
+		s.JobFailed(job, err)
+		return
+	}
+
+	s.publish(api_models.ScrapeRunEvent{
+		Type:        EventJobQueued,
+		CompanyName: job.CompanyName,
+		JobRole:     job.JobRole,
+		JobLink:     job.JobLink,
+	})
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *runSink) enqueueDetail(queued common.QueuedJob) error {
+	payload, err := json.Marshal(queued)
+	if err != nil {
+		return fmt.Errorf("failed to encode queued job: %w", err)
+	}
+
+	// A job listed again by a retried listing is only queued once
+	key := common.GetSHA256Hash(queued.Company.Name + "\n" + queued.Job.JobId + "\n" + queued.Job.JobLink + "\n" + string(queued.Detail))
+	return db.EnqueueScrapeTasks([]db.ScrapeTask{{
+		ScrapeRunID:    s.run.ID,
+		Kind:           db.ScrapeTaskDetail,
+		CareerSiteType: queued.Company.CareerSiteType,
+		Status:         db.ScrapeTaskPending,
+		Key:            db.ScrapeTaskDetail + ":" + key,
+		CompanyName:    queued.Company.Name,
+		Payload:        string(payload),
+	}})
+}
+
+func (s *runSink) JobScraped(job *db.Jobs) {
+	runCompany, ok := s.companies[job.CompanyName]
+	if !ok {
+		slog.Error("Scraped job for a company outside the run", "company", job.CompanyName, "run", s.run.ID)
+		return
+	}
+
+	inserted, err := common.InsertJob(job, runCompany.CareerSiteType+"_Scraper")
+
+	event := api_models.ScrapeRunEvent{
+		CompanyName: job.CompanyName,
+		JobRole:     job.JobRole,
+		JobLink:     job.JobLink,
+	}
+	switch {
+	case err != nil:
+		s.countJob(runCompany, "jobs_failed", err)
+		event.Type = EventJobFailed
+		event.Error = err.Error()
+	case inserted:
+		s.countJob(runCompany, "jobs_inserted", nil)
+		event.Type = EventJobInserted
+	default:
+		s.countJob(runCompany, "jobs_duplicate", nil)
+		event.Type = EventJobDuplicate
+	}
+
+	s.publish(event)
+}
+
+func (s *runSink) JobFailed(job *db.Jobs, err error) {
+	if runCompany, ok := s.companies[job.CompanyName]; ok {
+		s.countJob(runCompany, "jobs_failed", err)
+		// Jobs left over once the run was cancelled
+		if errors.Is(err, context.Canceled) {
+			if err := db.DB.Model(&db.ScrapeRunCompany{}).
+				Where("id = ? AND status = ?", runCompany.ID, db.ScrapeRunRunning).
+				Update("status", db.ScrapeRunCancelled).Error; err != nil {
+				slog.Error("Failed to record cancelled scrape run company", "run", s.run.ID, "company", runCompany.CompanyName, "error", err)
+			}
+		}
+	}
+
+	s.publish(api_models.ScrapeRunEvent{
+		Type:        EventJobFailed,
+		CompanyName: job.CompanyName,
+		JobRole:     job.JobRole,
+		JobLink:     job.JobLink,
+		Error:       err.Error(),
+	})
+}
+
+func (s *runSink) Warning(company db.Companies, message string) {
+	s.publish(api_models.ScrapeRunEvent{
+		Type:        EventWarning,
+		CompanyName: company.Name,
+		Error:       message,
+	})
+}
+
+func (s *runSink) CompanyListed(company db.Companies, jobsFound int, err error) {
+	runCompany, ok := s.companies[company.Name]
+	if !ok {
+		return
+	}
+
+	// Record listing results straight away so a running run shows progress
+	updates := map[string]interface{}{"jobs_listed": jobsFound}
+	if err != nil {
+		updates["status"] = db.ScrapeRunFailed
+		if errors.Is(err, context.Canceled) {
+			updates["status"] = db.ScrapeRunCancelled
+		}
+		updates["error"] = err.Error()
+	}
+	if dbErr := db.DB.Model(&db.ScrapeRunCompany{ID: runCompany.ID}).Updates(updates).Error; dbErr != nil {
+		slog.Error("Failed to record scrape run company", "run", s.run.ID, "company", company.Name, "error", dbErr)
+	}
+
+	event := api_models.ScrapeRunEvent{
+		Type:        EventCompanyListed,
+		CompanyName: company.Name,
+		Jobs:        &jobsFound,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.publish(event)
+}
+
+// finishCompanies closes the rows of companies that won't receive any more jobs, once their
+// provider has no task left. Companies closed before the run was resumed are left alone.
+func (s *runSink) finishCompanies(companies []db.Companies, failure string) {
+	now := time.Now()
+
+	names := make([]string, len(companies))
+	for i, company := range companies {
+		names[i] = company.Name
+	}
+
+	var runCompanies []db.ScrapeRunCompany
+	if err := db.DB.Where("scrape_run_id = ? AND company_name IN ?", s.run.ID, names).Find(&runCompanies).Error; err != nil {
+		slog.Error("Failed to fetch scrape run companies", "run", s.run.ID, "error", err)
+		return
+	}
+
+	var finished []db.ScrapeRunCompany
+	for _, runCompany := range runCompanies {
+		if runCompany.FinishedAt != nil {
+			continue
+		}
+		if failure != "" {
+			runCompany.Status = db.ScrapeRunFailed
+			runCompany.Error = failure
+		}
+		if runCompany.Status == db.ScrapeRunRunning {
+			runCompany.Status = db.ScrapeRunSucceeded
+			if runCompany.JobsFailed > 0 {
+				runCompany.Status = db.ScrapeRunPartial
+			}
+		}
+		runCompany.FinishedAt = &now
+
+		if err := db.DB.Model(&runCompany).Select("Status", "Error", "FinishedAt").Updates(&runCompany).Error; err != nil {
+			slog.Error("Failed to record scrape run company",
+				"run", runCompany.ScrapeRunID,
+				"company", runCompany.CompanyName,
+				"error", err)
+		}
+		finished = append(finished, runCompany)
+	}
+
+	// Cancelled companies stay due, the others count as scraped as of the start of the run
+	var scrapedNames []string
+	for _, runCompany := range finished {
+		if runCompany.Status != db.ScrapeRunCancelled {
+			scrapedNames = append(scrapedNames, runCompany.CompanyName)
+		}
+	}
+	if len(scrapedNames) > 0 {
+		if err := db.DB.Model(&db.Companies{}).Where("name IN ?", scrapedNames).
+			Update("last_scraped_at", s.run.StartedAt).Error; err != nil {
+			slog.Error("Failed to record last scrape of companies", "run", s.run.ID, "error", err)
+		}
+	}
+	s.recordHealth(finished, now)
+
+	for _, runCompany := range finished {
+		s.publish(api_models.ScrapeRunEvent{
+			Type:        EventCompanyFinished,
+			CompanyName: runCompany.CompanyName,
+			Status:      runCompany.Status,
+			Error:       runCompany.Error,
+		})
+	}
+}
+
+// finishRun totals the company results into the run and records it, a cancelled run keeps
+// the cancelled status whatever its companies' results
+func (s *runSink) finishRun(cancelled bool) {
+	now := time.Now()
+
+	run := *s.run
+	var runCompanies []db.ScrapeRunCompany
+	if err := db.DB.Where("scrape_run_id = ?", run.ID).Find(&runCompanies).Error; err != nil {
+		slog.Error("Failed to fetch scrape run companies", "run", run.ID, "error", err)
+	}
+
+	run.CompaniesFailed, run.JobsListed, run.JobsInserted, run.JobsDuplicate, run.JobsFailed = 0, 0, 0, 0, 0
+	partial := false
+	for _, runCompany := range runCompanies {
+		run.JobsListed += runCompany.JobsListed
+		run.JobsInserted += runCompany.JobsInserted
+		run.JobsDuplicate += runCompany.JobsDuplicate
+		run.JobsFailed += runCompany.JobsFailed
+		switch runCompany.Status {
+		case db.ScrapeRunFailed:
+			run.CompaniesFailed++
+		case db.ScrapeRunPartial:
+			partial = true
+		}
+	}
+
+	switch {
+	case cancelled:
+		run.Status = db.ScrapeRunCancelled
+	case run.CompaniesTotal > 0 && run.CompaniesFailed == run.CompaniesTotal:
+		run.Status = db.ScrapeRunFailed
+	case run.CompaniesFailed > 0 || partial:
+		run.Status = db.ScrapeRunPartial
+	default:
+		run.Status = db.ScrapeRunSucceeded
+	}
+	run.FinishedAt = &now
+	// Company rows are recorded on their own, keep GORM from upserting them again
+	run.Companies = nil
+
+	if err := db.DB.Model(&db.ScrapeRun{ID: run.ID}).Select(
+		"Status", "FinishedAt", "CompaniesFailed", "JobsListed", "JobsInserted", "JobsDuplicate", "JobsFailed",
+	).Updates(&run).Error; err != nil {
+		slog.Error("Failed to record scrape run", "run", run.ID, "error", err)
+	}
+	if err := db.DeleteFinishedScrapeTasks(run.ID); err != nil {
+		slog.Error("Failed to delete finished scrape tasks", "run", run.ID, "error", err)
+	}
+
+	slog.Info("Scrape run finished",
+		"run", run.ID,
+		"status", run.Status,
+		"companies", run.CompaniesTotal,
+		"companies_failed", run.CompaniesFailed,
+		"jobs_listed", run.JobsListed,
+		"jobs_inserted", run.JobsInserted,
+		"jobs_duplicate", run.JobsDuplicate,
+		"jobs_failed", run.JobsFailed)
+
+	// Callers waiting on the run read its outcome from here
+	s.run.Status = run.Status
+	s.run.FinishedAt = run.FinishedAt
+
+	run.Companies = runCompanies
+	runResponse := toScrapeRunResponse(run)
+	scrapeRunEvents.close(run.ID, &api_models.ScrapeRunEvent{
+		Type:   EventRunFinished,
+		RunID:  run.ID,
+		Time:   now.Format(time.RFC3339),
+		Status: run.Status,
+		Run:    &runResponse,
+	})
+}
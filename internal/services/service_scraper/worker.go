@@ -0,0 +1,162 @@
+package service_scraper
+
+import (
+	"context"
+	"encoding/json"
+	"job-scraper/internal/api_models"
+	"job-scraper/internal/db"
+	"job-scraper/internal/scraper"
+	"job-scraper/internal/types"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// localWork is false when the scrape runs started by this process leave their tasks to worker
+// processes
+var localWork = true
+
+// SetLocalWork sets whether the scrape runs started by this process work their own tasks, or only
+// wait for worker processes to work them
+func SetLocalWork(enabled bool) {
+	localWork = enabled
+}
+
+// Postgres channel the events of worker processes are relayed on
+const runEventsChannel = "scrape_run_events"
+
+// How often a worker looks for new scrape runs, and checks the ones it works are still running
+const workerPollInterval = 5 * time.Second
+
+// relayRunEvent sends an event of a worker process to the process streaming the run. Events are
+// best effort, one that can't be sent is dropped.
+func relayRunEvent(event api_models.ScrapeRunEvent) {
+	payload, err := json.Marshal(event)
+	if err == nil {
+		err = db.Notify(runEventsChannel, string(payload))
+	}
+	if err != nil {
+		slog.Debug("Dropped scrape run event", "run", event.RunID, "type", event.Type, "error", err)
+	}
+}
+
+// RelayWorkerEvents streams the events relayed by worker processes to the subscribers of this
+// process until ctx is done
+func RelayWorkerEvents(ctx context.Context) {
+	db.Listen(ctx, runEventsChannel, func(payload string) {
+		var event api_models.ScrapeRunEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			slog.Error("Invalid relayed scrape run event", "error", err)
+			return
+		}
+		scrapeRunEvents.publish(event)
+	})
+}
+
+// RunWorker works the tasks of the scrape runs started by other processes, alongside any other
+// worker, until Shutdown. Runs are recorded by the process that started them.
+func RunWorker() {
+	ticker := time.NewTicker(workerPollInterval)
+	defer ticker.Stop()
+
+	slog.Info("Scrape worker started")
+	for {
+		var runs []db.ScrapeRun
+		if err := db.DB.Preload("Companies").Where("status = ?", db.ScrapeRunRunning).Find(&runs).Error; err != nil {
+			slog.Error("Failed to fetch running scrape runs", "error", err)
+		}
+		for i := range runs {
+			workRun(&runs[i])
+		}
+
+		select {
+		case <-activeRuns.draining:
+			slog.Info("Scrape worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// workRun works the open tasks of a run in the background, unless this worker already is. It
+// stops once the run has no open task left, or stops running.
+func workRun(run *db.ScrapeRun) {
+	activeRuns.Lock()
+	if _, working := activeRuns.cancels[run.ID]; working || activeRuns.shuttingDown {
+		activeRuns.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	activeRuns.cancels[run.ID] = cancel
+	activeRuns.running.Add(1)
+	activeRuns.Unlock()
+
+	scrapeDayLimits := runScrapeDayLimits(run)
+	sink := newRunSink(run)
+	sink.remote = true
+
+	var wg sync.WaitGroup
+	seen := make(map[string]bool)
+	for _, runCompany := range run.Companies {
+		careerSiteType := runCompany.CareerSiteType
+		if seen[careerSiteType] {
+			continue
+		}
+		seen[careerSiteType] = true
+
+		// Providers without a scraper are closed by the process that started the run
+		jobScraper := scraper.JobScraperFactory(types.ScrapableWebsites(careerSiteType))
+		if jobScraper == nil {
+			continue
+		}
+		runner := &taskRunner{
+			ctx:             ctx,
+			run:             run,
+			sink:            sink,
+			careerSiteType:  careerSiteType,
+			jobScraper:      jobScraper,
+			scrapeDayLimits: scrapeDayLimits,
+			stopClaiming:    activeRuns.draining,
+		}
+		wg.Go(runner.work)
+	}
+
+	stopWatching := make(chan struct{})
+	go watchRun(run.ID, cancel, stopWatching)
+
+	go func() {
+		wg.Wait()
+		close(stopWatching)
+
+		activeRuns.Lock()
+		delete(activeRuns.cancels, run.ID)
+		activeRuns.Unlock()
+		cancel(nil)
+		activeRuns.running.Done()
+	}()
+}
+
+// watchRun cancels the work on a run once it stops running, like when it's cancelled, until stop
+// is closed
+func watchRun(runID uint, cancel context.CancelCauseFunc, stop <-chan struct{}) {
+	ticker := time.NewTicker(workerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		var run db.ScrapeRun
+		if err := db.DB.Select("id", "status").First(&run, runID).Error; err != nil {
+			slog.Error("Failed to fetch scrape run", "run", runID, "error", err)
+			continue
+		}
+		if run.Status != db.ScrapeRunRunning {
+			cancel(nil)
+			return
+		}
+	}
+}
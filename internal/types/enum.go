@@ -1,17 +1,16 @@
 package types
 
+// ScrapableWebsites names a scraper provider, the providers themselves are listed by the
+// registry in job-scraper/internal/scraper
 type ScrapableWebsites string
 
 const (
-	Workday     ScrapableWebsites = "workday"
-	Greenhouse  ScrapableWebsites = "greenhouse"
-	OracleCloud ScrapableWebsites = "oraclecloud"
+	Workday         ScrapableWebsites = "workday"
+	Greenhouse      ScrapableWebsites = "greenhouse"
+	OracleCloud     ScrapableWebsites = "oraclecloud"
+	Lever           ScrapableWebsites = "lever"
+	Ashby           ScrapableWebsites = "ashby"
+	SmartRecruiters ScrapableWebsites = "smartrecruiters"
+	JsonLD          ScrapableWebsites = "jsonld"
+	Custom          ScrapableWebsites = "custom"
 )
-
-func AllScrapableWebsites() []ScrapableWebsites {
-	return []ScrapableWebsites{
-		Workday,
-		Greenhouse,
-		OracleCloud,
-	}
-}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/csv"
+	"flag"
 	"fmt"
 	"job-scraper/internal"
 	"job-scraper/internal/types"
@@ -13,7 +14,9 @@ func main() {
 	// Set max days to scrape - jobs posted within the last X days will be scraped
 	// maxDaysToScrape := 1 // Scrape jobs posted within the last 1 day
 	// scrapeDateLimit := time.Now().AddDate(0, 0, -maxDaysToScrape)
-	internal.StartServer()
+	role := flag.String("role", internal.RoleAll, "api serves the API and records scrape runs, worker works their tasks, all does both")
+	flag.Parse()
+	internal.StartServer(*role)
 
 }
 